@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/wrkode/kasba/internal/util"
+)
+
+// rootContext returns the context all collection runs under. It is cancelled on SIGINT or SIGTERM and after
+// --timeout, so that the sections collected so far can still be rendered. A second signal terminates kasba.
+func rootContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if *util.TimeoutFlag <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, *util.TimeoutFlag)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
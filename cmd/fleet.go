@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/wrkode/kasba/internal/output"
+	"github.com/wrkode/kasba/internal/util"
+)
+
+// fleetSummaryName is the file the fleet summary is written to in --output-dir.
+const fleetSummaryName = "fleet-summary.txt"
+
+// RunAllContexts assesses every context of the kubeconfig. Each report is written to its own sub directory of
+// --output-dir, named after the context, and a summary table of all clusters is printed and saved. Once ctx is
+// done, the remaining contexts are skipped and listed as such in the summary. A context whose report cannot be
+// written is listed as failed. The exit code is the highest of all reports.
+func RunAllContexts(ctx context.Context) (int, error) {
+	if *util.OutputDirFlag == "" {
+		return 0, fmt.Errorf("--all-contexts requires --output-dir")
+	}
+	if *util.ContextFlag != "" {
+		return 0, fmt.Errorf("--context cannot be combined with --all-contexts")
+	}
+	// invalid output flags would fail every context, report them once instead
+	if _, err := outputFormats(); err != nil {
+		return 0, err
+	}
+	contexts, err := util.ListContexts()
+	if err != nil {
+		return 0, err
+	}
+
+	var reports []output.TemplateData
+	code := util.ExitOK
+	for _, context := range contexts {
+		if ctx.Err() != nil {
+			fmt.Fprintf(os.Stderr, "Skipping context %s: %v\n", context, ctx.Err())
+			skipped := output.SkippedContext(context, ctx.Err())
+			reports = append(reports, skipped)
+			if skipped.Diagnostics.ExitCode() > code {
+				code = skipped.Diagnostics.ExitCode()
+			}
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Assessing context %s\n", context)
+
+		kubeconfig = util.KubeConfig{Context: context}
+		diagnostics = nil
+		templateData = output.TemplateData{}
+
+		GetInfo(ctx)
+		templateData.Diagnostics = diagnostics
+		templateData.Context = context
+
+		// a report that cannot be written fails its context only, the remaining ones are still assessed
+		if err := render(templateData, filepath.Join(*util.OutputDirFlag, output.SafeFileName(context))); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write the report of context %s: %v\n", context, err)
+			templateData.Diagnostics.Add("Output", util.SeverityFatal, fmt.Errorf("report not written: %w", err))
+		}
+		reports = append(reports, templateData)
+		if templateData.Diagnostics.ExitCode() > code {
+			code = templateData.Diagnostics.ExitCode()
+		}
+	}
+
+	if err := output.AsFleetSummary(os.Stdout, reports); err != nil {
+		return 0, err
+	}
+	return code, output.WriteFile(filepath.Join(*util.OutputDirFlag, fleetSummaryName), func(w io.Writer, _ output.TemplateData) error {
+		return output.AsFleetSummary(w, reports)
+	}, output.TemplateData{})
+}
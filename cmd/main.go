@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"github.com/wrkode/kasba/internal/collector"
 	"github.com/wrkode/kasba/internal/output"
 	"github.com/wrkode/kasba/internal/util"
 	"log"
+	"os"
+	"strconv"
 	"time"
 
 	_ "embed"
@@ -16,15 +21,23 @@ const (
 )
 
 var (
-	createdAt    = time.Now().Format(time.RFC850)
+	createdAt    = creationTime().Truncate(time.Second)
 	Version      = ""
 	kubeconfig   util.KubeConfig
-	errors       util.Errors
+	diagnostics  util.Diagnostics
 	templateData output.TemplateData
 	//kasbaID   = uuid.New().String()
 )
 
-func GetInfo() {
+// creationTime honours SOURCE_DATE_EPOCH so that reproducible reports can be generated from the same cluster state.
+func creationTime() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+	return time.Now()
+}
+
+func GetInfo(ctx context.Context) {
 	var err error
 
 	templateData.CreatedAt = createdAt
@@ -32,102 +45,82 @@ func GetInfo() {
 	templateData.Version = Version
 
 	err = kubeconfig.GetKubeConfigPath()
-	if errors.Add(err, true) {
-		return // return because of fatal error
-	}
-
-	// Get Node Info
-	templateData.NodeInfo, err = kubeconfig.GetNodeInfo()
-	if errors.Add(err, true) {
+	if diagnostics.Add("Kubeconfig", util.SeverityFatal, err) {
 		return // return because of fatal error
 	}
+	templateData.Context = kubeconfig.ContextName()
+	templateData.KubeconfigCluster = kubeconfig.ClusterName()
 
-	if len(templateData.NodeInfo.Items) == 0 {
-		errors.Add(fmt.Errorf("unable to get nodeinfo"), true)
+	collectors, err := collector.Select(*util.IncludeSectionsFlag, *util.ExcludeSectionsFlag)
+	if diagnostics.Add("Sections", util.SeverityFatal, err) {
 		return // return because of fatal error
 	}
 
-	templateData.NetworkPlugin, err = kubeconfig.GetNetworkPluginPodName()
-	errors.Add(err, false)
-
-	templateData.Longhorn, err = kubeconfig.NamespaceExists("longhorn-system")
-	errors.Add(err, false)
-
-	if err != nil {
-		fmt.Println("Error checking if Longhorn is installed: ", err)
-	}
-
-	templateData.Monitoring, err = kubeconfig.NamespaceExists("cattle-monitoring-system")
-	errors.Add(err, false)
+	// Check which resources may be listed, so that forbidden sections are skipped instead of showing up empty
+	permissionsCtx, warnings := util.WithWarnings(ctx, "Permissions")
+	templateData.Permissions, err = kubeconfig.CheckPermissions(permissionsCtx)
+	diagnostics.Add("Permissions", util.SeverityWarning, err)
+	diagnostics = append(diagnostics, warnings.Diagnostics()...)
 
-	if err != nil {
-		fmt.Println("Error checking if Rancher Monitoring is installed: ", err)
-	}
-
-	templateData.WorkloadInfo, err = kubeconfig.GetWorkloads()
-	if err != nil {
-		fmt.Printf("Error getting apps %v\n:", err)
-	}
-
-	templateData.StorageClass, err = kubeconfig.GetStorageClasses()
-	if err != nil {
-		fmt.Printf("Error getting Storage Classes %v\n:", err)
-	}
-
-	templateData.PersistentVolumes, err = kubeconfig.GetPersistentVolumes()
-	if err != nil {
-		fmt.Printf("Error getting Persistent Volumes %v\n", err)
-	}
+	printPermissions(len(templateData.Permissions.Denied(collector.Dependencies(collectors)...)) > 0)
 
-	templateData.PersistentVolumeClaims, err = kubeconfig.GetPersistentVolumeClaims()
-	if err != nil {
-		fmt.Printf("Error getting Persistent Volume Claims %v\n", err)
-	}
-
-	templateData.ConfigMaps, err = kubeconfig.GetConfigMaps()
-	if err != nil {
-		fmt.Printf("Error getting ConfigMaps %v\n", err)
-	}
-
-	templateData.Services, err = kubeconfig.GetAllServices()
-	if err != nil {
-		fmt.Printf("Error getting Serices %v\n", err)
-	}
+	diagnostics = append(diagnostics, collector.Run(ctx, &kubeconfig, collectors, *util.ParallelismFlag, &templateData)...)
+}
 
-	templateData.Ingresses, err = kubeconfig.GetAllIngresses()
-	if err != nil {
-		fmt.Printf("Error getting Ingresses %v\n", err)
+// printPermissions prints the permission matrix on stderr, with a notice if the report will be missing resources
+// the selected sections need.
+func printPermissions(denied bool) {
+	fmt.Fprintln(os.Stderr, "Permissions:")
+	output.AsPermissionMatrix(os.Stderr, templateData.Permissions)
+	if denied {
+		fmt.Fprintln(os.Stderr, "Insufficient permissions, sections that need the resources that may not be listed are skipped or collected without them.")
 	}
+}
 
-	templateData.ClusterRoles, err = kubeconfig.GetAllClusterRoles()
+// Run executes the command line and exits with the exit code derived from the report's diagnostics, see
+// util.Diagnostics.ExitCode.
+func Run() {
+	util.Clock = creationTime
+	ctx, cancel := rootContext()
+	code, err := run(ctx)
+	cancel()
 	if err != nil {
-		fmt.Printf("Error getting ClusterRoles %v\n", err)
+		log.Fatal(err)
 	}
+	os.Exit(code)
+}
 
-	templateData.ClusterRoleBindings, err = kubeconfig.GetAllClusterRoleBindings()
-	if err != nil {
-		fmt.Printf("Error getting ClusterRoleBindings %v\n", err)
+func run(ctx context.Context) (int, error) {
+	var command func(ctx context.Context, args []string) error
+	switch flag.Arg(0) {
+	case "template":
+		command = Template
+	case "collect":
+		command = Collect
+	case "render":
+		command = Render
+	case "diff":
+		command = Diff
+	case "rbac":
+		command = RBAC
 	}
-
-	templateData.ServiceAccounts, err = kubeconfig.GetAllServiceAccounts()
-	if err != nil {
-		fmt.Printf("Error getting Service Accounts %v\n", err)
+	if command != nil {
+		if err := command(ctx, flag.Args()[1:]); err != nil {
+			return 0, err
+		}
+		return templateData.Diagnostics.ExitCode(), nil
 	}
 
-	templateData.NetworkPolicies, err = kubeconfig.GetAllNetworkPolicies()
-	if err != nil {
-		fmt.Printf("Error getting Network Policies %v\n", err)
+	if *util.AllContextsFlag {
+		return RunAllContexts(ctx)
 	}
-}
 
-func Run() {
+	GetInfo(ctx)
 
-	GetInfo()
+	templateData.Diagnostics = diagnostics
 
-	templateData.Errors = errors
-
-	err := output.AsText(templateData)
-	if err != nil {
-		log.Fatalf("Unable to Parse Template: %v", err)
+	if err := render(templateData, *util.OutputDirFlag); err != nil {
+		return 0, err
 	}
+	return templateData.Diagnostics.ExitCode(), nil
 }
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wrkode/kasba/internal/output"
+	"github.com/wrkode/kasba/internal/util"
+)
+
+// outputFormats returns the formats requested with --output, or the custom template given with --template.
+func outputFormats() ([]string, error) {
+	if *util.TemplateFlag != "" {
+		extension := "txt"
+		if ext := filepath.Ext(strings.TrimSuffix(filepath.Base(*util.TemplateFlag), ".tmpl")); ext != "" {
+			extension = strings.TrimPrefix(ext, ".")
+		}
+		output.Formats["template"] = output.Format{
+			Extension: extension,
+			Render: func(w io.Writer, data output.TemplateData) error {
+				return output.AsCustomTemplate(w, data, *util.TemplateFlag, *util.TemplateDirFlag)
+			},
+		}
+		return []string{"template"}, nil
+	}
+
+	var formats []string
+	for _, format := range strings.Split(*util.OutputFlag, ",") {
+		format = strings.TrimSpace(format)
+		if _, ok := output.Formats[format]; !ok {
+			return nil, fmt.Errorf("unknown output format %q", format)
+		}
+		formats = append(formats, format)
+	}
+	return formats, nil
+}
+
+// render writes the report in every requested format to stdout, --output-file or outputDir.
+func render(data output.TemplateData, outputDir string) error {
+	formats, err := outputFormats()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case outputDir != "":
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			return fmt.Errorf("unable to create output directory: %v", err)
+		}
+		for _, format := range formats {
+			name, err := output.FileName(*util.OutputNameFlag, data, format)
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(outputDir, name)
+			if err := output.WriteFile(path, output.Formats[format].Render, data); err != nil {
+				return fmt.Errorf("unable to write %s output to %s: %v", format, path, err)
+			}
+		}
+	case len(formats) > 1:
+		return fmt.Errorf("multiple output formats require --output-dir")
+	case *util.OutputFileFlag != "":
+		if err := output.WriteFile(*util.OutputFileFlag, output.Formats[formats[0]].Render, data); err != nil {
+			return fmt.Errorf("unable to write %s output to %s: %v", formats[0], *util.OutputFileFlag, err)
+		}
+	default:
+		if err := output.Formats[formats[0]].Render(os.Stdout, data); err != nil {
+			return fmt.Errorf("unable to render %s output: %v", formats[0], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wrkode/kasba/internal/output"
+	"github.com/wrkode/kasba/internal/util"
+	"sigs.k8s.io/yaml"
+)
+
+// RBAC implements "kasba rbac", which prints the minimal read-only ClusterRole kasba needs, or with --check the
+// permission matrix of the current credentials.
+func RBAC(ctx context.Context, args []string) error {
+	var name string
+	var check bool
+	positional, err := subcommandFlags("rbac", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&name, "name", "kasba-reader", "name of the generated ClusterRole")
+		fs.BoolVar(&check, "check", false, "check the permissions of the current credentials instead")
+	})
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("usage: kasba rbac [--name NAME] [--check]")
+	}
+
+	if check {
+		if err := kubeconfig.GetKubeConfigPath(); err != nil {
+			return err
+		}
+		permissions, err := kubeconfig.CheckPermissions(ctx)
+		if err != nil {
+			return err
+		}
+		return output.AsPermissionMatrix(os.Stdout, permissions)
+	}
+
+	role, err := yaml.Marshal(util.ReadOnlyClusterRole(name))
+	if err != nil {
+		return err
+	}
+	// the note goes first as YAML comments, so the output can still be piped to kubectl apply
+	for _, line := range strings.Split(util.CustomResourceRBACNote, "\n") {
+		fmt.Fprintln(os.Stdout, "# "+line)
+	}
+	_, err = os.Stdout.Write(role)
+	return err
+}
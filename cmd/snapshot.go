@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wrkode/kasba/internal/diff"
+	"github.com/wrkode/kasba/internal/output"
+	"github.com/wrkode/kasba/internal/util"
+)
+
+// subcommandFlags returns a flag set for a sub command that also accepts all global flags, and parses args with it.
+// Flags may appear before and after the positional arguments, which are returned.
+func subcommandFlags(name string, args []string, define func(fs *flag.FlagSet)) ([]string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	if define != nil {
+		define(fs)
+	}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// Collect implements "kasba collect -o FILE", which gathers the assessment and stores it as a snapshot.
+func Collect(ctx context.Context, args []string) error {
+	var snapshot string
+	_, err := subcommandFlags("collect", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&snapshot, "o", "kasba-snapshot.json.gz", "snapshot file, gzip compressed if it ends in .gz")
+	})
+	if err != nil {
+		return err
+	}
+
+	GetInfo(ctx)
+	templateData.Diagnostics = diagnostics
+
+	if err := output.WriteSnapshot(snapshot, templateData); err != nil {
+		return fmt.Errorf("unable to write snapshot %s: %v", snapshot, err)
+	}
+	fmt.Fprintf(os.Stderr, "Snapshot written to %s\n", snapshot)
+	return nil
+}
+
+// Render implements "kasba render SNAPSHOT", which renders a snapshot with the usual output flags.
+func Render(_ context.Context, args []string) error {
+	positional, err := subcommandFlags("render", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: kasba render SNAPSHOT [--output FORMATS] [--output-file FILE | --output-dir DIR]")
+	}
+
+	data, err := output.ReadSnapshot(positional[0])
+	if err != nil {
+		return err
+	}
+	return render(data, *util.OutputDirFlag)
+}
+
+// Diff implements "kasba diff OLD [NEW]", comparing two snapshots or a snapshot with the live cluster.
+func Diff(ctx context.Context, args []string) error {
+	positional, err := subcommandFlags("diff", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 1 || len(positional) > 2 {
+		return fmt.Errorf("usage: kasba diff OLD [NEW] [--output text|json] [--output-file FILE]")
+	}
+
+	old, err := output.ReadSnapshot(positional[0])
+	if err != nil {
+		return err
+	}
+
+	var current output.TemplateData
+	if len(positional) == 2 {
+		current, err = output.ReadSnapshot(positional[1])
+		if err != nil {
+			return err
+		}
+	} else {
+		GetInfo(ctx)
+		templateData.Diagnostics = diagnostics
+		if diagnostics.Fatal() {
+			return fmt.Errorf("unable to assess the live cluster: %s", diagnostics[len(diagnostics)-1].Message)
+		}
+		current = templateData
+	}
+
+	report := diff.Compare(old, current)
+	write := report.AsText
+	if *util.OutputFlag == "json" {
+		write = report.AsJSON
+	}
+
+	if *util.OutputFileFlag == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(*util.OutputFileFlag)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
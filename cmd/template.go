@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/wrkode/kasba/internal/templates"
+	"github.com/wrkode/kasba/internal/util"
+)
+
+// builtinTemplates maps the --output formats that are template based to their compiled-in template.
+var builtinTemplates = map[string]string{
+	"text":     templates.Text,
+	"html":     templates.HTML,
+	"markdown": templates.Markdown,
+}
+
+// Template implements the "kasba template" sub commands.
+func Template(_ context.Context, args []string) error {
+	positional, err := subcommandFlags("template", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || positional[0] != "dump" {
+		return fmt.Errorf("usage: kasba [--output text|html|markdown] template dump")
+	}
+
+	tmpl, ok := builtinTemplates[*util.OutputFlag]
+	if !ok {
+		return fmt.Errorf("output format %q has no built-in template", *util.OutputFlag)
+	}
+	_, err = fmt.Fprint(os.Stdout, tmpl)
+	return err
+}
@@ -0,0 +1,267 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wrkode/kasba/internal/output"
+	"github.com/wrkode/kasba/internal/util"
+)
+
+// Collector gathers one section of the report.
+type Collector interface {
+	// Name is the section name used in the report and diagnostics, e.g. "Storage Classes".
+	Name() string
+	// Dependencies are the API resources the collector lists, see util.Resources. The collector is skipped when
+	// the permission preflight found that any of them may not be listed.
+	Dependencies() []string
+	// Collect queries the cluster. The result is applied to the report even when an error is returned, so
+	// collectors can report partial data.
+	Collect(ctx context.Context, in Input) (Result, error)
+}
+
+// Input is what a collector works with: the API clients, and a copy of the report holding the results of the
+// sections collected before it, see SectionDependent. Collectors must not modify the report.
+type Input struct {
+	Clients *util.KubeConfig
+	Report  output.TemplateData
+}
+
+// DistributionSpecific is implemented by collectors that only apply to some Kubernetes distributions, e.g. because
+// they list resources only those install. They run once the Distribution section was collected, and not at all
+// when it detected another distribution. See util.DetectDistribution.
+type DistributionSpecific interface {
+	Collector
+	// Distributions are the names of the distributions the collector applies to, all if empty.
+	Distributions() []string
+}
+
+// OptionalDependent is implemented by collectors that list further resources when they may, and collect less
+// accurate results otherwise, e.g. detect a plugin without its configuration. Unlike Dependencies, optional
+// dependencies that may not be listed do not skip the collector, they are reported as a warning.
+type OptionalDependent interface {
+	Collector
+	// OptionalDependencies are the API resources the collector lists if it may, see util.Resources.
+	OptionalDependencies() []string
+}
+
+// SectionDependent is implemented by collectors that build on the results of other sections, e.g. the CNI
+// detection on the nodes. They run once those sections were collected. Sections that were not selected, were
+// skipped or failed are missing from Input.Report, and collectors make do without them.
+type SectionDependent interface {
+	Collector
+	// Needs are the names of the sections, registered before the collector, whose results it reads from
+	// Input.Report.
+	Needs() []string
+}
+
+// Essential is implemented by collectors without whose section there is no report, such as the nodes. When one is
+// skipped or fails, the diagnostic is fatal and the collectors that have not run yet are left out.
+type Essential interface {
+	Collector
+	Essential() bool
+}
+
+// Result stores what a collector gathered in its section of the report. The results of the collectors that run
+// together are applied one after another once all of them are done, so they do not need to synchronize.
+type Result func(data *output.TemplateData)
+
+var registry []Collector
+
+// Register adds a collector to the registry. Sections appear in the report, and their diagnostics, in the order
+// their collectors were registered.
+func Register(c Collector) {
+	for _, registered := range registry {
+		if ID(registered) == ID(c) {
+			panic(fmt.Sprintf("collector %q registered twice", c.Name()))
+		}
+	}
+	registry = append(registry, c)
+}
+
+// ID is the name of a collector as given to --include-sections and --exclude-sections: the section name in lower
+// case with spaces replaced by "-", e.g. "storage-classes".
+func ID(c Collector) string {
+	return strings.ReplaceAll(strings.ToLower(c.Name()), " ", "-")
+}
+
+// IDs returns the IDs of all registered collectors, sorted.
+func IDs() []string {
+	var ids []string
+	for _, c := range registry {
+		ids = append(ids, ID(c))
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Select returns the registered collectors named in include, or all if include is empty, minus those named in
+// exclude. Both are comma separated lists of collector IDs.
+func Select(include, exclude string) ([]Collector, error) {
+	included, err := parseIDs(include)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := parseIDs(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []Collector
+	for _, c := range registry {
+		if (len(included) == 0 || included[ID(c)]) && !excluded[ID(c)] {
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+func parseIDs(list string) (map[string]bool, error) {
+	known := map[string]bool{}
+	for _, c := range registry {
+		known[ID(c)] = true
+	}
+
+	ids := map[string]bool{}
+	for _, id := range strings.Split(list, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if !known[id] {
+			return nil, fmt.Errorf("unknown section %q, known sections are: %s", id, strings.Join(IDs(), ", "))
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+// applies reports whether c applies to the named distribution. When the distribution is unknown, only the
+// collectors that apply to all distributions do.
+func applies(c Collector, distribution string) bool {
+	specific, ok := c.(DistributionSpecific)
+	return !ok || len(specific.Distributions()) == 0 || contains(specific.Distributions(), distribution)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Dependencies returns the API resources the collectors list, including optional ones, without duplicates.
+func Dependencies(collectors []Collector) []string {
+	seen := map[string]bool{}
+	var resources []string
+	for _, c := range collectors {
+		all := append(append([]string(nil), c.Dependencies()...), optionalDependencies(c)...)
+		for _, resource := range all {
+			if !seen[resource] {
+				seen[resource] = true
+				resources = append(resources, resource)
+			}
+		}
+	}
+	return resources
+}
+
+func optionalDependencies(c Collector) []string {
+	if optional, ok := c.(OptionalDependent); ok {
+		return optional.OptionalDependencies()
+	}
+	return nil
+}
+
+// needs returns the sections c reads from its input, including the Distribution section for distribution specific
+// collectors.
+func needs(c Collector) []string {
+	var sections []string
+	if dependent, ok := c.(SectionDependent); ok {
+		sections = append(sections, dependent.Needs()...)
+	}
+	if specific, ok := c.(DistributionSpecific); ok && len(specific.Distributions()) > 0 {
+		sections = append(sections, DistributionSection)
+	}
+	return sections
+}
+
+func essential(c Collector) bool {
+	e, ok := c.(Essential)
+	return ok && e.Essential()
+}
+
+// section is a Collector built from a function, which is how the built-in sections are defined.
+type section struct {
+	name          string
+	dependencies  []string
+	optional      []string
+	distributions []string
+	needs         []string
+	essential     bool
+	collect       func(ctx context.Context, in Input) (Result, error)
+}
+
+func (s section) Name() string {
+	return s.name
+}
+
+func (s section) Dependencies() []string {
+	return s.dependencies
+}
+
+func (s section) OptionalDependencies() []string {
+	return s.optional
+}
+
+func (s section) Distributions() []string {
+	return s.distributions
+}
+
+func (s section) Needs() []string {
+	return s.needs
+}
+
+func (s section) Essential() bool {
+	return s.essential
+}
+
+func (s section) Collect(ctx context.Context, in Input) (Result, error) {
+	return s.collect(ctx, in)
+}
+
+// Option configures a Collector returned by New.
+type Option func(s *section)
+
+// For restricts the collector to the named distributions, see DistributionSpecific.
+func For(distributions ...string) Option {
+	return func(s *section) { s.distributions = distributions }
+}
+
+// Optional lists the resources the collector lists if it may, see OptionalDependent.
+func Optional(resources ...string) Option {
+	return func(s *section) { s.optional = resources }
+}
+
+// After names the sections the collector reads from its input, see SectionDependent.
+func After(sections ...string) Option {
+	return func(s *section) { s.needs = sections }
+}
+
+// Fatal makes the section essential to the report, see Essential.
+func Fatal() Option {
+	return func(s *section) { s.essential = true }
+}
+
+// New returns a Collector that calls collect, configured by options.
+func New(name string, dependencies []string, collect func(ctx context.Context, in Input) (Result, error), options ...Option) Collector {
+	s := section{name: name, dependencies: dependencies, collect: collect}
+	for _, option := range options {
+		option(&s)
+	}
+	return s
+}
@@ -0,0 +1,171 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/wrkode/kasba/internal/output"
+	"github.com/wrkode/kasba/internal/util"
+	"golang.org/x/term"
+)
+
+// Run runs the collectors with at most parallelism of them at a time and applies their results to data.
+// Collectors run in stages: each after the sections it needs, see SectionDependent, and the results of a stage are
+// applied before the next one starts. Distribution specific collectors that do not apply to the detected
+// distribution are left out; every other collector is listed in data.Sections, and those whose dependencies
+// data.Permissions denies are skipped. Denied optional dependencies are recorded as a warning, as are the warnings
+// of the API calls of each collector. Results and diagnostics are applied in the order of the collectors, not the
+// order they finish in, so the report does not depend on scheduling. When an essential collector is skipped or
+// fails, the later stages are not run. Once ctx is done, the collectors that have not started are skipped, and they
+// and the ones that failed because of it are listed in data.Incomplete.
+func Run(ctx context.Context, clients *util.KubeConfig, collectors []Collector, parallelism int, data *output.TemplateData) util.Diagnostics {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	diagnostics := make([]util.Diagnostics, len(collectors))
+	incomplete := make([]bool, len(collectors))
+	listed := make([]bool, len(collectors))
+	progress := newProgress(len(collectors))
+	slots := make(chan struct{}, parallelism)
+
+	fatal := false
+	for _, stage := range stages(collectors) {
+		if fatal {
+			break
+		}
+		results := make([]Result, len(collectors))
+		in := Input{Clients: clients, Report: *data}
+
+		var wg sync.WaitGroup
+		for _, i := range stage {
+			c := collectors[i]
+			if !applies(c, data.Distribution.Name) {
+				progress.done(c.Name())
+				continue
+			}
+			listed[i] = true
+			severity := util.SeverityError
+			if essential(c) {
+				severity = util.SeverityFatal
+			}
+			if denied := data.Permissions.Denied(c.Dependencies()...); len(denied) > 0 {
+				diagnostics[i] = util.Diagnostics{{
+					Section:   c.Name(),
+					Resource:  strings.Join(denied, ","),
+					Severity:  severity,
+					Class:     util.ClassForbidden,
+					Message:   "skipped: insufficient permissions to list " + strings.Join(denied, ", "),
+					Timestamp: util.Clock(),
+				}}
+				progress.done(c.Name())
+				continue
+			}
+			if denied := data.Permissions.Denied(optionalDependencies(c)...); len(denied) > 0 {
+				diagnostics[i] = util.Diagnostics{{
+					Section:   c.Name(),
+					Resource:  strings.Join(denied, ","),
+					Severity:  util.SeverityWarning,
+					Class:     util.ClassForbidden,
+					Message:   "collected without " + strings.Join(denied, ", ") + ": insufficient permissions to list them",
+					Timestamp: util.Clock(),
+				}}
+			}
+			wg.Add(1)
+			go func(i int, c Collector) {
+				defer wg.Done()
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-ctx.Done():
+					incomplete[i] = true
+					return
+				}
+				collectCtx, warnings := util.WithWarnings(ctx, c.Name())
+				result, err := c.Collect(collectCtx, in)
+				results[i] = result
+				incomplete[i] = diagnostics[i].Add(c.Name(), severity, err) && ctx.Err() != nil
+				diagnostics[i] = append(diagnostics[i], warnings.Diagnostics()...)
+				progress.done(c.Name())
+			}(i, c)
+		}
+		wg.Wait()
+
+		for _, i := range stage {
+			if results[i] != nil {
+				results[i](data)
+			}
+			fatal = fatal || diagnostics[i].Fatal() || (incomplete[i] && essential(collectors[i]))
+		}
+	}
+	progress.finish()
+
+	var all util.Diagnostics
+	for i, c := range collectors {
+		if !listed[i] {
+			continue
+		}
+		data.Sections = append(data.Sections, c.Name())
+		if incomplete[i] {
+			data.Incomplete = append(data.Incomplete, c.Name())
+			if !essential(c) {
+				continue
+			}
+		}
+		all = append(all, diagnostics[i]...)
+	}
+	if ctx.Err() != nil {
+		all.Add("", util.SeverityError, fmt.Errorf("collection stopped before all sections completed: %w", ctx.Err()))
+	}
+	return all
+}
+
+// stages groups the indexes of the collectors into the stages Run runs them in: a collector runs in the stage after
+// the last of the sections it needs. Needed sections that are not among the collectors are ignored.
+func stages(collectors []Collector) [][]int {
+	stageOf := map[string]int{}
+	var stages [][]int
+	for i, c := range collectors {
+		stage := 0
+		for _, name := range needs(c) {
+			if needed, ok := stageOf[name]; ok && needed+1 > stage {
+				stage = needed + 1
+			}
+		}
+		stageOf[c.Name()] = stage
+		for len(stages) <= stage {
+			stages = append(stages, nil)
+		}
+		stages[stage] = append(stages[stage], i)
+	}
+	return stages
+}
+
+// progress prints the number of collected sections on stderr, if stderr is a terminal.
+type progress struct {
+	mu        sync.Mutex
+	enabled   bool
+	total     int
+	completed int
+}
+
+func newProgress(total int) *progress {
+	return &progress{enabled: term.IsTerminal(int(os.Stderr.Fd())), total: total}
+}
+
+func (p *progress) done(section string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.completed++
+	if p.enabled {
+		fmt.Fprintf(os.Stderr, "\r\033[KCollecting [%d/%d] %s", p.completed, p.total, section)
+	}
+}
+
+func (p *progress) finish() {
+	if p.enabled {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
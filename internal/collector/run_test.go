@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wrkode/kasba/internal/output"
+	"github.com/wrkode/kasba/internal/util"
+)
+
+// fake returns a collector that records the distribution it saw in Input.Report in data.Context, and fails with
+// err if set.
+func fake(name string, deps []string, err error, options ...Option) Collector {
+	return New(name, deps, func(ctx context.Context, in Input) (Result, error) {
+		seen := in.Report.Distribution.Name
+		return func(data *output.TemplateData) { data.Context += name + ":" + seen + ";" }, err
+	}, options...)
+}
+
+// distribution returns a collector that detects the named distribution.
+func distribution(name string) Collector {
+	return New(DistributionSection, nil, func(ctx context.Context, in Input) (Result, error) {
+		return func(data *output.TemplateData) { data.Distribution.Name = name }, nil
+	})
+}
+
+func TestRun(t *testing.T) {
+	denied := util.Permissions{{Resource: util.Resource{Resource: "nodes"}, Allowed: false}}
+
+	tests := []struct {
+		name         string
+		collectors   []Collector
+		permissions  util.Permissions
+		wantContext  string
+		wantSections []string
+		wantFatal    bool
+	}{
+		{
+			name:         "needed sections are applied first",
+			collectors:   []Collector{distribution("K3s"), fake("A", nil, nil, After(DistributionSection)), fake("B", nil, nil)},
+			wantContext:  "B:;A:K3s;",
+			wantSections: []string{"Distribution", "A", "B"},
+		},
+		{
+			name:         "distribution filter",
+			collectors:   []Collector{distribution("EKS"), fake("Helm Charts", nil, nil, For("K3s")), fake("EKS Addons", nil, nil, For("EKS"))},
+			wantContext:  "EKS Addons:EKS;",
+			wantSections: []string{"Distribution", "EKS Addons"},
+		},
+		{
+			name:         "missing needed section",
+			collectors:   []Collector{fake("A", nil, nil, After(NodesSection))},
+			wantContext:  "A:;",
+			wantSections: []string{"A"},
+		},
+		{
+			name:         "essential section fails",
+			collectors:   []Collector{fake(NodesSection, nil, errors.New("unreachable"), Fatal()), fake("A", nil, nil, After(NodesSection)), fake("B", nil, nil)},
+			wantContext:  "Nodes:;B:;",
+			wantSections: []string{NodesSection, "B"},
+			wantFatal:    true,
+		},
+		{
+			name:         "essential section denied",
+			collectors:   []Collector{fake(NodesSection, []string{"nodes"}, nil, Fatal()), fake("A", nil, nil, After(NodesSection))},
+			permissions:  denied,
+			wantSections: []string{NodesSection},
+			wantFatal:    true,
+		},
+		{
+			name:         "other section denied",
+			collectors:   []Collector{fake("A", []string{"nodes"}, nil), fake("B", nil, nil, After("A"))},
+			permissions:  denied,
+			wantContext:  "B:;",
+			wantSections: []string{"A", "B"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := output.TemplateData{Permissions: tt.permissions}
+			diagnostics := Run(context.Background(), nil, tt.collectors, 2, &data)
+			if data.Context != tt.wantContext {
+				t.Errorf("results applied as %q, want %q", data.Context, tt.wantContext)
+			}
+			if !reflect.DeepEqual(data.Sections, tt.wantSections) {
+				t.Errorf("Sections = %q, want %q", data.Sections, tt.wantSections)
+			}
+			if diagnostics.Fatal() != tt.wantFatal {
+				t.Errorf("Fatal() = %v, want %v: %+v", diagnostics.Fatal(), tt.wantFatal, diagnostics)
+			}
+		})
+	}
+}
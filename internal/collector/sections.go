@@ -0,0 +1,113 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wrkode/kasba/internal/output"
+	"github.com/wrkode/kasba/internal/util"
+)
+
+// Names of the sections other collectors build on.
+const (
+	NodesSection        = "Nodes"
+	DistributionSection = "Distribution"
+)
+
+// The built-in sections, in report order.
+func init() {
+	Register(New(NodesSection, []string{"nodes"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetNodeInfo(ctx)
+		if err == nil && len(value.Items) == 0 {
+			err = fmt.Errorf("unable to get nodeinfo")
+		}
+		return func(data *output.TemplateData) { data.NodeInfo = value }, err
+	}, Fatal()))
+	Register(New(DistributionSection, nil, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetDistribution(ctx, in.Report.NodeInfo)
+		return func(data *output.TemplateData) { data.Distribution = value }, err
+	}, Optional("namespaces", "configmaps", "pods"), After(NodesSection)))
+	Register(New("API Server", nil, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetAPIServerInfo(ctx)
+		value.KubeletSkew = util.KubeletSkew(value.GitVersion, in.Report.NodeInfo)
+		return func(data *output.TemplateData) { data.APIServer = value }, err
+	}, After(NodesSection)))
+	Register(New("CNI", nil, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetNetworkPlugin(ctx, in.Report.NodeInfo)
+		return func(data *output.TemplateData) { data.NetworkPlugin = value }, err
+	}, Optional("daemonsets", "configmaps", "customresourcedefinitions"), After(NodesSection)))
+	Register(New("Longhorn", []string{"namespaces"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.NamespaceExists(ctx, "longhorn-system")
+		return func(data *output.TemplateData) { data.Longhorn = value }, err
+	}))
+	Register(New("Monitoring", []string{"namespaces"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.NamespaceExists(ctx, "cattle-monitoring-system")
+		return func(data *output.TemplateData) { data.Monitoring = value }, err
+	}))
+	Register(New("Workloads", []string{"deployments", "daemonsets", "statefulsets"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetWorkloads(ctx)
+		return func(data *output.TemplateData) { data.WorkloadInfo = value }, err
+	}))
+	Register(New("Storage Classes", []string{"storageclasses"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetStorageClasses(ctx)
+		return func(data *output.TemplateData) { data.StorageClass = value }, err
+	}))
+	Register(New("Persistent Volumes", []string{"persistentvolumes"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetPersistentVolumes(ctx)
+		return func(data *output.TemplateData) { data.PersistentVolumes = value }, err
+	}))
+	Register(New("Persistent Volume Claims", []string{"persistentvolumeclaims"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetPersistentVolumeClaims(ctx)
+		return func(data *output.TemplateData) { data.PersistentVolumeClaims = value }, err
+	}))
+	Register(New("ConfigMaps", []string{"configmaps"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetConfigMaps(ctx)
+		return func(data *output.TemplateData) { data.ConfigMaps = value }, err
+	}))
+	Register(New("Services", []string{"services"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetAllServices(ctx)
+		return func(data *output.TemplateData) { data.Services = value }, err
+	}))
+	Register(New("Ingresses", []string{"ingresses"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetAllIngresses(ctx)
+		return func(data *output.TemplateData) { data.Ingresses = value }, err
+	}))
+	Register(New("ClusterRoles", []string{"clusterroles"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetAllClusterRoles(ctx)
+		return func(data *output.TemplateData) { data.ClusterRoles = value }, err
+	}))
+	Register(New("ClusterRoleBindings", []string{"clusterrolebindings"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetAllClusterRoleBindings(ctx)
+		return func(data *output.TemplateData) { data.ClusterRoleBindings = value }, err
+	}))
+	Register(New("Service Accounts", []string{"serviceaccounts"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetAllServiceAccounts(ctx)
+		return func(data *output.TemplateData) { data.ServiceAccounts = value }, err
+	}))
+	Register(New("Network Policies", []string{"networkpolicies"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetAllNetworkPolicies(ctx)
+		return func(data *output.TemplateData) { data.NetworkPolicies = value }, err
+	}))
+	Register(New("Container Images", []string{"pods", "replicasets"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetContainerImages(ctx)
+		return func(data *output.TemplateData) { data.ContainerImages = value }, err
+	}))
+	// distribution specific sections, for the resources only those distributions install
+	Register(New("Cluster Version", []string{"clusterversions"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetClusterVersions(ctx)
+		return func(data *output.TemplateData) { data.ClusterVersions = value }, err
+	}, For(util.DistributionOpenShift)))
+	Register(New("Helm Charts", []string{"helmcharts"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetHelmCharts(ctx)
+		return func(data *output.TemplateData) { data.HelmCharts = value }, err
+	}, For(util.DistributionRKE2, util.DistributionK3s)))
+	Register(New("Custom Resource Definitions", []string{"customresourcedefinitions"}, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetCustomResourceDefinitions(ctx)
+		return func(data *output.TemplateData) { data.CustomResourceDefinitions = value }, err
+	}))
+	// the resources of the --custom-resource kinds are only known after discovery, GetCustomResources checks them
+	Register(New("Custom Resources", nil, func(ctx context.Context, in Input) (Result, error) {
+		value, err := in.Clients.GetCustomResources(ctx, util.CustomResourceFlag)
+		return func(data *output.TemplateData) { data.CustomResources = value }, err
+	}))
+}
@@ -0,0 +1,278 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wrkode/kasba/internal/output"
+	"github.com/wrkode/kasba/internal/util"
+)
+
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is a single difference between two assessments.
+type Change struct {
+	Section string `json:"section"`
+	Type    string `json:"type"` // added, removed or changed
+	Name    string `json:"name"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// NotCompared is a section left out of the comparison because one of the assessments lacks it in full.
+type NotCompared struct {
+	Section string `json:"section"`
+	Reason  string `json:"reason"` // e.g. "incomplete in the new assessment"
+}
+
+// Report lists the changes between an old and a new assessment, ordered by section and name.
+type Report struct {
+	Old         time.Time     `json:"old"` // CreatedAt of the old assessment
+	New         time.Time     `json:"new"` // CreatedAt of the new assessment
+	Changes     []Change      `json:"changes"`
+	NotCompared []NotCompared `json:"notCompared"`
+}
+
+// sections are compared in this order. collector is the name of the section that collects the data, as used in
+// the sections, incomplete and diagnostics fields of an assessment.
+var sections = []struct {
+	name      string
+	collector string
+	compare   func(r *Report, old, new output.TemplateData)
+}{
+	{"Nodes", "Nodes", (*Report).compareNodes},
+	{"Workloads", "Workloads", (*Report).compareWorkloads},
+	{"Storage Classes", "Storage Classes", (*Report).compareStorageClasses},
+	{"Cluster Role Bindings", "ClusterRoleBindings", (*Report).compareClusterRoleBindings},
+	{"Network Policies", "Network Policies", (*Report).compareNetworkPolicies},
+}
+
+// Compare returns the differences between old and new, section by section. Sections that are missing, incomplete,
+// skipped or failed in either assessment are not compared, since their items would show up as removed or added.
+func Compare(old, new output.TemplateData) Report {
+	report := Report{Old: old.CreatedAt, New: new.CreatedAt, Changes: []Change{}, NotCompared: []NotCompared{}}
+	for _, section := range sections {
+		reason := uncollected(old, section.collector, "old")
+		if reason == "" {
+			reason = uncollected(new, section.collector, "new")
+		}
+		if reason != "" {
+			report.NotCompared = append(report.NotCompared, NotCompared{Section: section.name, Reason: reason})
+			continue
+		}
+		section.compare(&report, old, new)
+	}
+	return report
+}
+
+// uncollected returns why section is missing or incomplete in the assessment called which, or "" if it is complete.
+// Assessments written before sections were recorded are taken to include every section.
+func uncollected(data output.TemplateData, section, which string) string {
+	for _, incomplete := range data.Incomplete {
+		if incomplete == section {
+			return "incomplete in the " + which + " assessment"
+		}
+	}
+	for _, d := range data.Diagnostics {
+		if d.Section != section || d.Severity == util.SeverityWarning {
+			continue
+		}
+		if d.Class == util.ClassForbidden {
+			return "skipped for lack of permissions in the " + which + " assessment"
+		}
+		return "failed in the " + which + " assessment"
+	}
+	if data.Sections == nil {
+		return ""
+	}
+	for _, collected := range data.Sections {
+		if collected == section {
+			return ""
+		}
+	}
+	return "not collected in the " + which + " assessment"
+}
+
+func (r *Report) add(section, changeType, name, detail string) {
+	r.Changes = append(r.Changes, Change{Section: section, Type: changeType, Name: name, Detail: detail})
+}
+
+// compareSets reports names only present in one of the maps, and calls changed for names present in both.
+func (r *Report) compareSets(section string, old, new map[string]interface{}, changed func(name string, old, new interface{})) {
+	for _, name := range sortedKeys(old) {
+		if _, ok := new[name]; !ok {
+			r.add(section, Removed, name, "")
+		}
+	}
+	for _, name := range sortedKeys(new) {
+		oldItem, ok := old[name]
+		if !ok {
+			r.add(section, Added, name, "")
+		} else if changed != nil {
+			changed(name, oldItem, new[name])
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (r *Report) compareNodes(old, new output.TemplateData) {
+	kubeletVersions := func(data output.TemplateData) map[string]interface{} {
+		nodes := map[string]interface{}{}
+		for _, node := range data.NodeInfo.Items {
+			nodes[node.Name] = node.System.KubeletVersion
+		}
+		return nodes
+	}
+	r.compareSets("Nodes", kubeletVersions(old), kubeletVersions(new), func(name string, old, new interface{}) {
+		if old != new {
+			r.add("Nodes", Changed, name, fmt.Sprintf("kubelet version %s -> %s", old, new))
+		}
+	})
+}
+
+func (r *Report) compareWorkloads(old, new output.TemplateData) {
+	workloads := func(data output.TemplateData) map[string]interface{} {
+		items := map[string]interface{}{}
+		for _, ns := range data.WorkloadInfo.Namespaces {
+			for _, workloadType := range ns.WorkloadTypes {
+				for _, name := range workloadType.Workloads {
+					items[ns.Namespace+"/"+workloadType.WorkloadType+"/"+name] = nil
+				}
+			}
+		}
+		return items
+	}
+	r.compareSets("Workloads", workloads(old), workloads(new), nil)
+}
+
+func (r *Report) compareStorageClasses(old, new output.TemplateData) {
+	storageClasses := func(data output.TemplateData) map[string]interface{} {
+		items := map[string]interface{}{}
+		for _, sc := range data.StorageClass {
+			items[sc.Name] = sc
+		}
+		return items
+	}
+	r.compareSets("Storage Classes", storageClasses(old), storageClasses(new), func(name string, oldItem, newItem interface{}) {
+		oldSC, newSC := oldItem.(util.StorageClassItem), newItem.(util.StorageClassItem)
+
+		var details []string
+		if oldSC.Provisioner != newSC.Provisioner {
+			details = append(details, fmt.Sprintf("provisioner %s -> %s", oldSC.Provisioner, newSC.Provisioner))
+		}
+
+		keys := map[string]interface{}{}
+		for key := range oldSC.Parameters {
+			keys[key] = nil
+		}
+		for key := range newSC.Parameters {
+			keys[key] = nil
+		}
+		for _, key := range sortedKeys(keys) {
+			oldValue, inOld := oldSC.Parameters[key]
+			newValue, inNew := newSC.Parameters[key]
+			switch {
+			case !inOld:
+				details = append(details, fmt.Sprintf("parameter %s added (%s)", key, newValue))
+			case !inNew:
+				details = append(details, fmt.Sprintf("parameter %s removed (was %s)", key, oldValue))
+			case oldValue != newValue:
+				details = append(details, fmt.Sprintf("parameter %s %s -> %s", key, oldValue, newValue))
+			}
+		}
+		if len(details) > 0 {
+			r.add("Storage Classes", Changed, name, strings.Join(details, ", "))
+		}
+	})
+}
+
+func (r *Report) compareClusterRoleBindings(old, new output.TemplateData) {
+	bindings := func(data output.TemplateData) map[string]interface{} {
+		items := map[string]interface{}{}
+		for _, crb := range data.ClusterRoleBindings {
+			items[crb.Name] = crb
+		}
+		return items
+	}
+	r.compareSets("Cluster Role Bindings", bindings(old), bindings(new), func(name string, oldItem, newItem interface{}) {
+		if !sameJSON(oldItem, newItem) {
+			r.add("Cluster Role Bindings", Changed, name, "role or subjects changed")
+		}
+	})
+}
+
+func (r *Report) compareNetworkPolicies(old, new output.TemplateData) {
+	policies := func(data output.TemplateData) map[string]interface{} {
+		items := map[string]interface{}{}
+		for _, netPol := range data.NetworkPolicies {
+			items[netPol.Namespace+"/"+netPol.Name] = netPol
+		}
+		return items
+	}
+	r.compareSets("Network Policies", policies(old), policies(new), func(name string, oldItem, newItem interface{}) {
+		if !sameJSON(oldItem, newItem) {
+			r.add("Network Policies", Changed, name, "selector, policy types or rules changed")
+		}
+	})
+}
+
+// sameJSON compares two values by their JSON representation, which ignores the internal state of types such as
+// resource.Quantity or intstr.IntOrString.
+func sameJSON(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aJSON) == string(bJSON)
+}
+
+// AsText writes a human readable summary of the report.
+func (r Report) AsText(w io.Writer) error {
+	fmt.Fprintf(w, "Comparing assessment from %s with %s\n", r.Old.Format(time.RFC850), r.New.Format(time.RFC850))
+	if len(r.Changes) == 0 {
+		fmt.Fprintln(w, "No changes.")
+	} else {
+		symbols := map[string]string{Added: "+", Removed: "-", Changed: "~"}
+		section := ""
+		for _, change := range r.Changes {
+			if change.Section != section {
+				section = change.Section
+				fmt.Fprintf(w, "\n%s:\n", section)
+			}
+			fmt.Fprintf(w, "  %s %s", symbols[change.Type], change.Name)
+			if change.Detail != "" {
+				fmt.Fprintf(w, ": %s", change.Detail)
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "\n%d change(s)\n", len(r.Changes))
+	}
+
+	if len(r.NotCompared) > 0 {
+		fmt.Fprintln(w, "\nNot compared:")
+		for _, section := range r.NotCompared {
+			fmt.Fprintf(w, "  %s: %s\n", section.Section, section.Reason)
+		}
+	}
+	return nil
+}
+
+// AsJSON writes the report as JSON.
+func (r Report) AsJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
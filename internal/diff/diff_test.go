@@ -0,0 +1,124 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/wrkode/kasba/internal/output"
+	"github.com/wrkode/kasba/internal/util"
+)
+
+func nodes(versions ...string) util.NodeInfo {
+	var info util.NodeInfo
+	for i := 0; i+1 < len(versions); i += 2 {
+		node := util.NodeItem{Name: versions[i]}
+		node.System.KubeletVersion = versions[i+1]
+		info.Items = append(info.Items, node)
+	}
+	return info
+}
+
+func binding(name, role string, users ...string) util.ClusterRoleBindingItem {
+	crb := util.ClusterRoleBindingItem{Name: name, RoleName: role}
+	for _, user := range users {
+		crb.Subjects = append(crb.Subjects, rbacv1.Subject{Kind: rbacv1.UserKind, Name: user})
+	}
+	return crb
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name        string
+		old, new    output.TemplateData
+		want        []Change
+		notCompared []string
+	}{
+		{
+			name: "no changes",
+			old:  output.TemplateData{NodeInfo: nodes("n1", "v1.27.3")},
+			new:  output.TemplateData{NodeInfo: nodes("n1", "v1.27.3")},
+		},
+		{
+			name: "node added",
+			old:  output.TemplateData{NodeInfo: nodes("n1", "v1.27.3")},
+			new:  output.TemplateData{NodeInfo: nodes("n1", "v1.27.3", "n2", "v1.27.3")},
+			want: []Change{{Section: "Nodes", Type: Added, Name: "n2"}},
+		},
+		{
+			name: "node removed",
+			old:  output.TemplateData{NodeInfo: nodes("n1", "v1.27.3", "n2", "v1.27.3")},
+			new:  output.TemplateData{NodeInfo: nodes("n2", "v1.27.3")},
+			want: []Change{{Section: "Nodes", Type: Removed, Name: "n1"}},
+		},
+		{
+			name: "kubelet upgraded",
+			old:  output.TemplateData{NodeInfo: nodes("n1", "v1.27.3")},
+			new:  output.TemplateData{NodeInfo: nodes("n1", "v1.28.2")},
+			want: []Change{{Section: "Nodes", Type: Changed, Name: "n1", Detail: "kubelet version v1.27.3 -> v1.28.2"}},
+		},
+		{
+			name: "cluster role binding added",
+			old:  output.TemplateData{ClusterRoleBindings: []util.ClusterRoleBindingItem{binding("admins", "cluster-admin", "alice")}},
+			new: output.TemplateData{ClusterRoleBindings: []util.ClusterRoleBindingItem{
+				binding("admins", "cluster-admin", "alice"), binding("viewers", "view", "bob"),
+			}},
+			want: []Change{{Section: "Cluster Role Bindings", Type: Added, Name: "viewers"}},
+		},
+		{
+			name: "cluster role binding subjects changed",
+			old:  output.TemplateData{ClusterRoleBindings: []util.ClusterRoleBindingItem{binding("admins", "cluster-admin", "alice")}},
+			new:  output.TemplateData{ClusterRoleBindings: []util.ClusterRoleBindingItem{binding("admins", "cluster-admin", "alice", "bob")}},
+			want: []Change{{Section: "Cluster Role Bindings", Type: Changed, Name: "admins", Detail: "role or subjects changed"}},
+		},
+		{
+			name:        "section incomplete",
+			old:         output.TemplateData{NodeInfo: nodes("n1", "v1.27.3")},
+			new:         output.TemplateData{NodeInfo: nodes("n2", "v1.27.3"), Incomplete: []string{"Nodes"}},
+			notCompared: []string{"Nodes"},
+		},
+		{
+			name: "section forbidden",
+			old:  output.TemplateData{ClusterRoleBindings: []util.ClusterRoleBindingItem{binding("admins", "cluster-admin", "alice")}},
+			new: output.TemplateData{Diagnostics: util.Diagnostics{
+				{Section: "ClusterRoleBindings", Severity: util.SeverityError, Class: util.ClassForbidden},
+			}},
+			notCompared: []string{"Cluster Role Bindings"},
+		},
+		{
+			name: "warnings do not prevent the comparison",
+			old:  output.TemplateData{NodeInfo: nodes("n1", "v1.27.3")},
+			new: output.TemplateData{NodeInfo: nodes("n1", "v1.27.3", "n2", "v1.27.3"), Diagnostics: util.Diagnostics{
+				{Section: "Nodes", Severity: util.SeverityWarning},
+			}},
+			want: []Change{{Section: "Nodes", Type: Added, Name: "n2"}},
+		},
+		{
+			name:        "section not collected",
+			old:         output.TemplateData{NodeInfo: nodes("n1", "v1.27.3"), Sections: []string{"Nodes"}},
+			new:         output.TemplateData{NodeInfo: nodes("n1", "v1.27.3"), Sections: []string{"Nodes", "Workloads"}},
+			notCompared: []string{"Workloads", "Storage Classes", "Cluster Role Bindings", "Network Policies"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Compare(tt.old, tt.new)
+
+			want := tt.want
+			if want == nil {
+				want = []Change{}
+			}
+			if !reflect.DeepEqual(report.Changes, want) {
+				t.Errorf("Changes = %+v, want %+v", report.Changes, want)
+			}
+			var notCompared []string
+			for _, section := range report.NotCompared {
+				notCompared = append(notCompared, section.Section)
+			}
+			if !reflect.DeepEqual(notCompared, tt.notCompared) {
+				t.Errorf("NotCompared = %v, want %v", notCompared, tt.notCompared)
+			}
+		})
+	}
+}
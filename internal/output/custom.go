@@ -0,0 +1,35 @@
+package output
+
+import (
+	"io"
+	"path/filepath"
+	"text/template"
+)
+
+// AsCustomTemplate renders data with a user supplied template file. When partialsDir is set, every *.tmpl file in it
+// is parsed as well, so the main template can pull in shared blocks with {{ template "name.tmpl" . }} or any
+// {{ define "name" }} the partials contain. The helpers from FuncMap are available to all of them.
+func AsCustomTemplate(w io.Writer, data TemplateData, path string, partialsDir string) error {
+	name := filepath.Base(path)
+	tmpl := template.New(name).Funcs(FuncMap())
+
+	if partialsDir != "" {
+		partials, err := filepath.Glob(filepath.Join(partialsDir, "*.tmpl"))
+		if err != nil {
+			return err
+		}
+		if len(partials) > 0 {
+			tmpl, err = tmpl.ParseFiles(partials...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// parse the main template last so its definitions win over a partial with the same file name
+	tmpl, err := tmpl.ParseFiles(path)
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, name, data)
+}
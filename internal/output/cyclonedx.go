@@ -0,0 +1,172 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const CycloneDXSpecVersion = "1.5"
+
+// CycloneDX document types, limited to the fields kasba fills in.
+// See https://cyclonedx.org/docs/1.5/json/ for the full schema.
+type cycloneDXBOM struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp  string              `json:"timestamp,omitempty"`
+	Tools      []cycloneDXTool     `json:"tools"`
+	Component  cycloneDXComponent  `json:"component"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef     string              `json:"bom-ref"`
+	Type       string              `json:"type"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Hashes     []cycloneDXHash     `json:"hashes,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// imageReference splits a container image reference into repository, tag and digest.
+func imageReference(image string, digest string) (repository string, tag string, imageDigest string) {
+	repository = image
+	if i := strings.Index(repository, "@"); i >= 0 {
+		imageDigest = repository[i+1:]
+		repository = repository[:i]
+	}
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		tag = repository[i+1:]
+		repository = repository[:i]
+	}
+	if digest != "" {
+		imageDigest = digest
+	}
+	return repository, tag, imageDigest
+}
+
+// imagePURL builds a package URL of type oci, https://github.com/package-url/purl-spec/blob/master/PURL-TYPES.rst#oci
+func imagePURL(repository string, tag string, digest string) string {
+	name := strings.ToLower(repository[strings.LastIndex(repository, "/")+1:])
+	purl := "pkg:oci/" + name
+	if digest != "" {
+		purl += "@" + url.QueryEscape(digest)
+	}
+
+	query := url.Values{}
+	query.Set("repository_url", repository)
+	if tag != "" {
+		query.Set("tag", tag)
+	}
+	return purl + "?" + query.Encode()
+}
+
+// cycloneDXComponents turns the container inventory into one component per distinct image, with a property for
+// every workload container that runs it.
+func cycloneDXComponents(data TemplateData) []cycloneDXComponent {
+	var components []cycloneDXComponent
+	index := map[string]int{}
+	for _, item := range data.ContainerImages {
+		repository, tag, digest := imageReference(item.Image, item.Digest)
+		ref := repository
+		if tag != "" {
+			ref += ":" + tag
+		}
+		if digest != "" {
+			ref += "@" + digest
+		}
+
+		i, ok := index[ref]
+		if !ok {
+			component := cycloneDXComponent{
+				BOMRef:  ref,
+				Type:    "container",
+				Name:    repository,
+				Version: tag,
+				PURL:    imagePURL(repository, tag, digest),
+			}
+			if algorithm, content, found := strings.Cut(digest, ":"); found && algorithm == "sha256" {
+				component.Hashes = []cycloneDXHash{{Alg: "SHA-256", Content: content}}
+			}
+			i = len(components)
+			index[ref] = i
+			components = append(components, component)
+		}
+
+		container := item.Container
+		if item.InitContainer {
+			container += " (init)"
+		}
+		components[i].Properties = append(components[i].Properties,
+			cycloneDXProperty{Name: "kasba:namespace", Value: item.Namespace},
+			cycloneDXProperty{Name: "kasba:workload", Value: fmt.Sprintf("%s/%s/%s/%s", item.Namespace, item.OwnerKind, item.OwnerName, container)},
+		)
+	}
+	return components
+}
+
+func AsCycloneDX(w io.Writer, data TemplateData) error {
+	components := cycloneDXComponents(data)
+
+	// derive the serial number from the content, so unchanged clusters keep the same BOM
+	content, err := json.Marshal(components)
+	if err != nil {
+		return err
+	}
+
+	timestamp := data.createdAtRFC3339()
+	bom := cycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  CycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + uuid.NewSHA1(uuid.NameSpaceURL, append([]byte(data.NodeInfo.Cluster+timestamp), content...)).String(),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: timestamp,
+			Tools:     []cycloneDXTool{{Name: "kasba", Version: data.toolVersion()}},
+			Component: cycloneDXComponent{
+				BOMRef: "cluster",
+				Type:   "platform",
+				Name:   data.NodeInfo.Cluster,
+			},
+		},
+		Components: components,
+	}
+	// diagnostics tell BOM consumers that the inventory may be incomplete
+	for _, line := range data.diagnosticLines() {
+		bom.Metadata.Properties = append(bom.Metadata.Properties, cycloneDXProperty{Name: "kasba:diagnostic", Value: line})
+	}
+	for _, section := range data.Incomplete {
+		bom.Metadata.Properties = append(bom.Metadata.Properties, cycloneDXProperty{Name: "kasba:incomplete", Value: section})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bom)
+}
@@ -1,28 +1,72 @@
 package output
 
 import (
-	"github.com/wrkode/kasba/internal/nodeinfo"
+	"fmt"
+	"time"
+
 	"github.com/wrkode/kasba/internal/util"
 )
 
 type TemplateData struct {
-	CreatedAt              string
-	BOMFormat              string
-	Version                string
-	NodeInfo               nodeinfo.NodesInfo
-	NetworkPlugin          string
-	Longhorn               bool
-	Monitoring             bool
-	WorkloadInfo           util.WorkloadInfo
-	StorageClass           []util.StorageClassItem
-	PersistentVolumes      []util.PersistentVolumeItem
-	PersistentVolumeClaims []util.PersistentVolumeClaimItem
-	ConfigMaps             []util.ConfigMapItem
-	Services               []util.ServiceItem
-	Ingresses              []util.IngressItem
-	ClusterRoles           []util.ClusterRoleItem
-	ClusterRoleBindings    []util.ClusterRoleBindingItem
-	ServiceAccounts        []util.ServiceAccountItem
-	NetworkPolicies                []util.NetworkPolicyItem
-	Errors                 util.Errors
+	CreatedAt                 time.Time                           `json:"createdAt"`
+	BOMFormat                 string                              `json:"bomFormat"`
+	Version                   string                              `json:"version"`
+	Context                   string                              `json:"context"`
+	KubeconfigCluster         string                              `json:"kubeconfigCluster,omitempty"` // cluster entry of the kubeconfig context
+	Permissions               util.Permissions                    `json:"permissions"`
+	Distribution              util.Distribution                   `json:"distribution"`
+	APIServer                 util.APIServerInfo                  `json:"apiServer"`
+	NodeInfo                  util.NodeInfo                       `json:"nodeInfo"`
+	NetworkPlugin             util.NetworkPlugin                  `json:"networkPlugin"`
+	Longhorn                  bool                                `json:"longhorn"`
+	Monitoring                bool                                `json:"monitoring"`
+	WorkloadInfo              util.WorkloadInfo                   `json:"workloadInfo"`
+	StorageClass              []util.StorageClassItem             `json:"storageClasses"`
+	PersistentVolumes         []util.PersistentVolumeItem         `json:"persistentVolumes"`
+	PersistentVolumeClaims    []util.PersistentVolumeClaimItem    `json:"persistentVolumeClaims"`
+	ConfigMaps                []util.ConfigMapItem                `json:"configMaps"`
+	Services                  []util.ServiceItem                  `json:"services"`
+	Ingresses                 []util.IngressItem                  `json:"ingresses"`
+	ClusterRoles              []util.ClusterRoleItem              `json:"clusterRoles"`
+	ClusterRoleBindings       []util.ClusterRoleBindingItem       `json:"clusterRoleBindings"`
+	ServiceAccounts           []util.ServiceAccountItem           `json:"serviceAccounts"`
+	NetworkPolicies           []util.NetworkPolicyItem            `json:"networkPolicies"`
+	ContainerImages           []util.ContainerImageItem           `json:"containerImages"`
+	ClusterVersions           []util.ClusterVersionItem           `json:"clusterVersions,omitempty"` // OpenShift only
+	HelmCharts                []util.HelmChartItem                `json:"helmCharts,omitempty"`      // RKE2 and K3s only
+	CustomResourceDefinitions []util.CustomResourceDefinitionItem `json:"customResourceDefinitions"`
+	CustomResources           []util.CustomResourceDump           `json:"customResources,omitempty"` // kinds selected with --custom-resource
+	Sections                  []string                            `json:"sections,omitempty"`        // sections run, after --include-sections, --exclude-sections and the distribution filter
+	Diagnostics               util.Diagnostics                    `json:"diagnostics"`
+	Incomplete                []string                            `json:"incomplete,omitempty"` // sections not collected because collection was interrupted
+}
+
+// createdAtRFC3339 returns CreatedAt in the RFC 3339 form SBOM formats require, or "" if it is not set.
+func (t TemplateData) createdAtRFC3339() string {
+	if t.CreatedAt.IsZero() {
+		return ""
+	}
+	return t.CreatedAt.UTC().Format(time.RFC3339)
+}
+
+// toolVersion returns the kasba version that wrote the report for SBOM tool information, "dev" for builds without
+// a version.
+func (t TemplateData) toolVersion() string {
+	if t.Version == "" {
+		return "dev"
+	}
+	return t.Version
+}
+
+// diagnosticLines formats the diagnostics one per line, for formats that only have room for free text.
+func (t TemplateData) diagnosticLines() []string {
+	var lines []string
+	for _, d := range t.Diagnostics {
+		line := fmt.Sprintf("[%s/%s] ", d.Severity, d.Class)
+		if d.Section != "" {
+			line += d.Section + ": "
+		}
+		lines = append(lines, line+d.Message)
+	}
+	return lines
 }
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/wrkode/kasba/internal/util"
+)
+
+// skippedSection is the diagnostic section of reports for contexts that were not assessed.
+const skippedSection = "Context"
+
+// SkippedContext returns the report of a context that was not assessed because err, e.g. an interrupt, stopped
+// the fleet run first. AsFleetSummary lists it as skipped.
+func SkippedContext(context string, err error) TemplateData {
+	return TemplateData{
+		Context:     context,
+		Diagnostics: util.Diagnostics{util.NewDiagnostic(skippedSection, util.SeverityError, fmt.Errorf("not assessed: %w", err))},
+	}
+}
+
+// AsFleetSummary writes one table row per assessed context: nodes, kubelet versions, CNI, workload count and
+// the number of errors collected.
+func AsFleetSummary(w io.Writer, reports []TemplateData) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONTEXT\tCLUSTER\tNODES\tKUBELET\tCNI\tWORKLOADS\tSTATUS")
+	for _, data := range reports {
+		versions := map[string]bool{}
+		for _, node := range data.NodeInfo.Items {
+			versions[node.System.KubeletVersion] = true
+		}
+		var kubelets []string
+		for version := range versions {
+			kubelets = append(kubelets, version)
+		}
+		sort.Strings(kubelets)
+
+		workloads := 0
+		for _, ns := range data.WorkloadInfo.Namespaces {
+			for _, workloadType := range ns.WorkloadTypes {
+				workloads += len(workloadType.Workloads)
+			}
+		}
+
+		status := "ok"
+		switch {
+		case len(data.Diagnostics) == 1 && data.Diagnostics[0].Section == skippedSection:
+			status = "skipped"
+		case data.Diagnostics.Fatal():
+			status = "failed"
+		case len(data.Incomplete) > 0:
+			status = "incomplete"
+		case data.Diagnostics.HasErrors():
+			status = fmt.Sprintf("%d error(s)", data.Diagnostics.Count(util.SeverityError))
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%d\t%s\n",
+			data.Context, data.ClusterName(), len(data.NodeInfo.Items), strings.Join(kubelets, ","),
+			data.NetworkPlugin.Name, workloads, status)
+	}
+	return tw.Flush()
+}
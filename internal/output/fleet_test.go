@@ -0,0 +1,45 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wrkode/kasba/internal/util"
+)
+
+func TestAsFleetSummaryStatus(t *testing.T) {
+	diagnostics := func(severity util.Severity) util.Diagnostics {
+		var d util.Diagnostics
+		d.Add("Output", severity, errors.New("report not written"))
+		return d
+	}
+
+	tests := []struct {
+		name   string
+		report TemplateData
+		want   string
+	}{
+		{"ok", TemplateData{}, "ok"},
+		{"skipped", SkippedContext("prod", context.Canceled), "skipped"},
+		{"render failed", TemplateData{Diagnostics: diagnostics(util.SeverityFatal)}, "failed"},
+		{"incomplete", TemplateData{Incomplete: []string{"Workloads"}}, "incomplete"},
+		{"errors", TemplateData{Diagnostics: diagnostics(util.SeverityError)}, "1 error(s)"},
+		{"warnings", TemplateData{Diagnostics: diagnostics(util.SeverityWarning)}, "ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.report.Context = "prod"
+			var out bytes.Buffer
+			if err := AsFleetSummary(&out, []TemplateData{tt.report}); err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if got := lines[len(lines)-1]; !strings.HasSuffix(got, "  "+tt.want) {
+				t.Errorf("summary row %q, want status %q", got, tt.want)
+			}
+		})
+	}
+}
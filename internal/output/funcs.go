@@ -0,0 +1,293 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/yaml"
+)
+
+// FuncMap returns the helper functions available to the built-in templates and to templates passed with --template.
+// Field arguments are dotted paths into the list items, e.g. "Namespace" or "Metadata.Name"; helpers that take a
+// list accept it as the last argument so they can be used in pipelines.
+//
+//	humanizeAge NOW TIME        age of TIME at NOW in kubectl style, e.g. "12d"; both are a metav1.Time, time.Time
+//	                            or RFC3339 string, NOW usually the report's $.CreatedAt
+//	groupBy FIELD LIST          ordered groups of LIST items sharing FIELD, each with .Key and .Items
+//	sortBy FIELD LIST           copy of LIST sorted by FIELD
+//	sumQuantity FIELD LIST      sum of the resource quantities (or quantity strings) in FIELD, e.g. "35Gi"
+//	join SEP LIST               items of LIST joined with SEP
+//	default DEFAULT VALUE       VALUE, or DEFAULT when VALUE is empty
+//	toYaml VALUE, toJson VALUE  VALUE serialized with the field names of the JSON output
+//	upper STRING                STRING in upper case
+//	indent SPACES STRING        STRING with every line indented by SPACES spaces
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"humanizeAge": humanizeAge,
+		"groupBy":     groupBy,
+		"sortBy":      sortBy,
+		"sumQuantity": sumQuantity,
+		"join":        join,
+		"default":     defaultValue,
+		"toYaml":      toYaml,
+		"toJson":      toJson,
+		"upper":       strings.ToUpper,
+		"indent":      indent,
+	}
+}
+
+// Group is a set of list items sharing the same value of the field passed to groupBy.
+type Group struct {
+	Key   string
+	Items []interface{}
+}
+
+func humanizeAge(reference interface{}, value interface{}) (string, error) {
+	now, err := ageTime(reference)
+	if err != nil {
+		return "", err
+	}
+	created, err := ageTime(value)
+	if err != nil {
+		return "", err
+	}
+	if now.IsZero() || created.IsZero() {
+		return "<unknown>", nil
+	}
+	return duration.HumanDuration(now.Sub(created)), nil
+}
+
+// ageTime returns the time of a humanizeAge argument, the zero time for a nil *metav1.Time.
+func ageTime(value interface{}) (time.Time, error) {
+	switch t := value.(type) {
+	case metav1.Time:
+		return t.Time, nil
+	case *metav1.Time:
+		if t == nil {
+			return time.Time{}, nil
+		}
+		return t.Time, nil
+	case time.Time:
+		return t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("humanizeAge: %v", err)
+		}
+		return parsed, nil
+	default:
+		return time.Time{}, fmt.Errorf("humanizeAge: unsupported type %T", value)
+	}
+}
+
+func groupBy(field string, list interface{}) ([]Group, error) {
+	items, err := listItems(list)
+	if err != nil {
+		return nil, fmt.Errorf("groupBy: %v", err)
+	}
+
+	var groups []Group
+	index := map[string]int{}
+	for _, item := range items {
+		value, err := fieldValue(item, field)
+		if err != nil {
+			return nil, fmt.Errorf("groupBy: %v", err)
+		}
+		key := fmt.Sprint(value.Interface())
+		if i, ok := index[key]; ok {
+			groups[i].Items = append(groups[i].Items, item)
+			continue
+		}
+		index[key] = len(groups)
+		groups = append(groups, Group{Key: key, Items: []interface{}{item}})
+	}
+	return groups, nil
+}
+
+func sortBy(field string, list interface{}) ([]interface{}, error) {
+	items, err := listItems(list)
+	if err != nil {
+		return nil, fmt.Errorf("sortBy: %v", err)
+	}
+
+	values := make([]reflect.Value, len(items))
+	for i, item := range items {
+		values[i], err = fieldValue(item, field)
+		if err != nil {
+			return nil, fmt.Errorf("sortBy: %v", err)
+		}
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return lessValue(values[order[i]], values[order[j]])
+	})
+
+	sorted := make([]interface{}, len(items))
+	for i, o := range order {
+		sorted[i] = items[o]
+	}
+	return sorted, nil
+}
+
+func sumQuantity(field string, list interface{}) (string, error) {
+	var sum resource.Quantity
+	items, err := listItems(list)
+	if err != nil {
+		return "", fmt.Errorf("sumQuantity: %v", err)
+	}
+
+	for _, item := range items {
+		value, err := fieldValue(item, field)
+		if err != nil {
+			return "", fmt.Errorf("sumQuantity: %v", err)
+		}
+		switch q := value.Interface().(type) {
+		case resource.Quantity:
+			sum.Add(q)
+		case *resource.Quantity:
+			if q != nil {
+				sum.Add(*q)
+			}
+		case string:
+			if q == "" {
+				continue
+			}
+			parsed, err := resource.ParseQuantity(q)
+			if err != nil {
+				return "", fmt.Errorf("sumQuantity: %v", err)
+			}
+			sum.Add(parsed)
+		default:
+			return "", fmt.Errorf("sumQuantity: field %s has unsupported type %T", field, q)
+		}
+	}
+	return sum.String(), nil
+}
+
+func join(sep string, list interface{}) (string, error) {
+	items, err := listItems(list)
+	if err != nil {
+		return "", fmt.Errorf("join: %v", err)
+	}
+
+	values := make([]string, len(items))
+	for i, item := range items {
+		values[i] = fmt.Sprint(item)
+	}
+	return strings.Join(values, sep), nil
+}
+
+func defaultValue(def interface{}, value interface{}) interface{} {
+	if value == nil {
+		return def
+	}
+	v := reflect.ValueOf(value)
+	if v.IsZero() {
+		return def
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return def
+		}
+	}
+	return value
+}
+
+func toYaml(value interface{}) (string, error) {
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("toYaml: %v", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func toJson(value interface{}) (string, error) {
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("toJson: %v", err)
+	}
+	return string(out), nil
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// listItems flattens a slice or array of any element type into a []interface{}.
+func listItems(list interface{}) ([]interface{}, error) {
+	if list == nil {
+		return nil, nil
+	}
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a list, got %T", list)
+	}
+
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items, nil
+}
+
+// fieldValue resolves a dotted path of struct fields and map keys on item.
+func fieldValue(item interface{}, path string) (reflect.Value, error) {
+	v := reflect.ValueOf(item)
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, fmt.Errorf("nil value in field path %s", path)
+			}
+			v = v.Elem()
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			v = v.FieldByName(name)
+			if !v.IsValid() {
+				return reflect.Value{}, fmt.Errorf("%T has no field %s", item, name)
+			}
+		case reflect.Map:
+			v = v.MapIndex(reflect.ValueOf(name))
+			if !v.IsValid() {
+				v = reflect.ValueOf("")
+			}
+		default:
+			return reflect.Value{}, fmt.Errorf("cannot resolve %s on %T", name, item)
+		}
+	}
+	return v, nil
+}
+
+// lessValue compares numbers numerically and everything else by its printed form.
+func lessValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if b.Kind() == a.Kind() {
+			return a.Int() < b.Int()
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if b.Kind() == a.Kind() {
+			return a.Uint() < b.Uint()
+		}
+	case reflect.Float32, reflect.Float64:
+		if b.Kind() == a.Kind() {
+			return a.Float() < b.Float()
+		}
+	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+}
@@ -0,0 +1,36 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHumanizeAge(t *testing.T) {
+	createdAt := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		now     interface{}
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{"metav1.Time", createdAt, metav1.NewTime(createdAt.Add(-12 * 24 * time.Hour)), "12d", false},
+		{"time.Time", createdAt, createdAt.Add(-90 * time.Minute), "90m", false},
+		{"RFC3339", createdAt.Format(time.RFC3339), "2023-08-01T11:59:30Z", "30s", false},
+		{"nil", createdAt, (*metav1.Time)(nil), "<unknown>", false},
+		{"zero", createdAt, metav1.Time{}, "<unknown>", false},
+		{"report without time", time.Time{}, createdAt, "<unknown>", false},
+		{"invalid", createdAt, "yesterday", "", true},
+		{"unsupported", createdAt, 42, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := humanizeAge(tt.now, tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("humanizeAge() = %q, %v, want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
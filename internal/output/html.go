@@ -0,0 +1,34 @@
+package output
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/wrkode/kasba/internal/templates"
+	v1 "k8s.io/api/core/v1"
+)
+
+// conditionClass maps a node condition to the CSS class used to highlight it: "bad" (red) for a node that is not
+// Ready, "warn" (amber) for any other condition that is set or unknown, and "" when the condition is healthy.
+func conditionClass(conditionType v1.NodeConditionType, status v1.ConditionStatus) string {
+	if conditionType == v1.NodeReady {
+		if status == v1.ConditionTrue {
+			return ""
+		}
+		return "bad"
+	}
+	if status == v1.ConditionFalse {
+		return ""
+	}
+	return "warn"
+}
+
+func AsHTML(w io.Writer, data TemplateData) error {
+	funcs := template.FuncMap(FuncMap())
+	funcs["conditionClass"] = conditionClass
+	tmpl, err := template.New("as_html").Funcs(funcs).Parse(templates.HTML)
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, "as_html", data)
+}
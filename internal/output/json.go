@@ -0,0 +1,63 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONSchemaVersion is bumped whenever a field of the JSON document is renamed, removed or changes meaning.
+// Adding new fields does not change the version.
+//
+// Document layout (version 1):
+//
+//	schemaVersion           string, always "1"
+//	createdAt               RFC 3339 time of collection
+//	bomFormat, version, context
+//	kubeconfigCluster       cluster entry of the kubeconfig context
+//	permissions             [{group, resource, allowed, reason}] for every resource kasba lists
+//	distribution            {name, provider, evidence, clusterName}, the detected Kubernetes distribution, e.g. RKE2
+//	                        or EKS, and the cluster name it recorded on the nodes
+//	apiServer               {gitVersion, gitCommit, buildDate, goVersion, platform, groups: [{groupVersion,
+//	                        preferred, resources}], kubeletSkew: [{node, kubeletVersion, reason}]}
+//	nodeInfo                {cluster, items: [{name, labels, annotations, taints, addresses, capacity, allocatable,
+//	                        conditions, system, ...}]}, the API server URL and the nodes sorted by name
+//	networkPlugin           {name, version, mode, evidence, secondary}, the CNI with the version from its image tag,
+//	                        its mode, e.g. VXLAN or kube-proxy replacement, and further CNIs such as Multus
+//	longhorn, monitoring
+//	workloadInfo            workloads grouped by namespace and type
+//	storageClasses, persistentVolumes, persistentVolumeClaims, configMaps
+//	services, ingresses
+//	clusterRoles, clusterRoleBindings, serviceAccounts, networkPolicies
+//	                        namespaced objects carry their createdAt timestamp, ages are only computed when rendering
+//	containerImages         one entry per container, with the resolved digest and owning workload
+//	clusterVersions         [{name, version, channel, clusterID, history: [{version, state, startedTime}]}],
+//	                        OpenShift only
+//	helmCharts              [{name, namespace, chart, version, repo, targetNamespace}], RKE2 and K3s only
+//	customResourceDefinitions
+//	                        [{name, group, kind, plural, scope, versions, storedVersions, total, instances}], every
+//	                        CRD with the number of its objects per namespace;
+//	                        total is omitted if they could not be counted
+//	customResources         [{group, version, kind, resource, items}] for every kind given to --custom-resource
+//	sections                names of the sections that were run, after --include-sections, --exclude-sections and the
+//	                        distribution filter
+//	diagnostics             [{section, resource, severity, class, message, timestamp}], severity is warning, error or
+//	                        fatal and class is forbidden, notfound, timeout, interrupted or other
+//	incomplete              names of the sections not collected because collection was interrupted, if any
+//
+// All lists are sorted by namespace and name, map keys are sorted and the only times taken from the clock, createdAt
+// and the diagnostic timestamps, honour SOURCE_DATE_EPOCH, so the same cluster state renders byte-identical.
+const JSONSchemaVersion = "1"
+
+type jsonDocument struct {
+	SchemaVersion string `json:"schemaVersion"`
+	TemplateData
+}
+
+func AsJSON(w io.Writer, data TemplateData) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonDocument{
+		SchemaVersion: JSONSchemaVersion,
+		TemplateData:  data,
+	})
+}
@@ -0,0 +1,37 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/wrkode/kasba/internal/templates"
+)
+
+var markdownCellReplacer = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>", "<", "&lt;", ">", "&gt;")
+
+// markdownCell formats a value so it can be placed in a single GitHub-flavoured Markdown table cell.
+func markdownCell(value interface{}) string {
+	if _, ok := value.(fmt.Stringer); !ok && value != nil {
+		// template arguments are passed by value, so String methods with a pointer receiver, e.g. that of
+		// resource.Quantity, are only found on a copy
+		copied := reflect.New(reflect.TypeOf(value))
+		copied.Elem().Set(reflect.ValueOf(value))
+		if stringer, ok := copied.Interface().(fmt.Stringer); ok {
+			value = stringer
+		}
+	}
+	return markdownCellReplacer.Replace(fmt.Sprint(value))
+}
+
+func AsMarkdown(w io.Writer, data TemplateData) error {
+	funcs := FuncMap()
+	funcs["cell"] = markdownCell
+	tmpl, err := template.New("as_markdown").Funcs(funcs).Parse(templates.Markdown)
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, "as_markdown", data)
+}
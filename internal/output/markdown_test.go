@@ -0,0 +1,53 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/wrkode/kasba/internal/util"
+)
+
+func TestMarkdownCell(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"string", "a|b", `a\|b`},
+		{"newline", "a\nb", "a<br>b"},
+		{"html", "<none>", "&lt;none&gt;"},
+		{"quantity value", resource.MustParse("1Gi"), "1Gi"},
+		{"quantity pointer", resource.NewQuantity(500, resource.DecimalSI), "500"},
+		{"nil", nil, "&lt;nil&gt;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownCell(tt.value); got != tt.want {
+				t.Errorf("markdownCell() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAsMarkdownQuantities(t *testing.T) {
+	data := TemplateData{
+		NodeInfo:               util.NodeInfo{Items: []util.NodeItem{{Name: "n1"}}},
+		PersistentVolumes:      []util.PersistentVolumeItem{{Name: "pv1", Size: resource.MustParse("1Gi")}},
+		PersistentVolumeClaims: []util.PersistentVolumeClaimItem{{Namespace: "default", Name: "pvc1", Capacity: resource.MustParse("1Gi")}},
+	}
+	var out bytes.Buffer
+	if err := AsMarkdown(&out, data); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"| pv1 | 1Gi |", "| default | pvc1 |  |  | 1Gi |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("markdown output does not contain %q", want)
+		}
+	}
+	if strings.Contains(out.String(), "BinarySI") {
+		t.Errorf("markdown output contains an unformatted quantity")
+	}
+}
@@ -0,0 +1,26 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/wrkode/kasba/internal/util"
+)
+
+// AsPermissionMatrix writes one table row per resource kasba collects, showing whether it may be listed.
+func AsPermissionMatrix(w io.Writer, permissions util.Permissions) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RESOURCE\tGROUP\tLIST\tREASON")
+	for _, item := range permissions {
+		group, allowed := item.Group, "no"
+		if group == "" {
+			group = "core"
+		}
+		if item.Allowed {
+			allowed = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", item.Resource.Resource, group, allowed, item.Reason)
+	}
+	return tw.Flush()
+}
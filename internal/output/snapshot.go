@@ -0,0 +1,57 @@
+package output
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// WriteSnapshot stores data as a JSON document, the same one --output json produces, so it can be rendered later
+// without access to the cluster. Paths ending in .gz are gzip compressed.
+func WriteSnapshot(path string, data TemplateData) error {
+	return WriteFile(path, func(w io.Writer, data TemplateData) error {
+		if !strings.HasSuffix(path, ".gz") {
+			return AsJSON(w, data)
+		}
+		gz := gzip.NewWriter(w)
+		if err := AsJSON(gz, data); err != nil {
+			return err
+		}
+		return gz.Close()
+	}, data)
+}
+
+// ReadSnapshot loads a snapshot written by WriteSnapshot or by --output json. Gzip compression is detected
+// from the content, not the file name. Only snapshots of the current schema version can be read.
+func ReadSnapshot(path string) (TemplateData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return TemplateData{}, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	gz, err := gzip.NewReader(f)
+	if err == nil {
+		defer gz.Close()
+		r = gz
+	} else if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return TemplateData{}, err
+	}
+
+	var document jsonDocument
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return TemplateData{}, fmt.Errorf("unable to read snapshot %s: %v", path, err)
+	}
+	if document.SchemaVersion != JSONSchemaVersion {
+		writtenBy := document.Version
+		if writtenBy == "" {
+			writtenBy = "an unknown version"
+		}
+		return TemplateData{}, fmt.Errorf("unable to read snapshot %s: schema version %q is not supported, this kasba reads version %s, it was written by kasba %s, render it with that release", path, document.SchemaVersion, JSONSchemaVersion, writtenBy)
+	}
+	return document.TemplateData, nil
+}
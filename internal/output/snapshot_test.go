@@ -0,0 +1,56 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	data := TemplateData{CreatedAt: time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC), Version: "v1.2.0", Context: "prod"}
+	for _, name := range []string{"current.json", "current.json.gz"} {
+		if err := WriteSnapshot(filepath.Join(dir, name), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{name: "current", file: "current.json"},
+		{name: "gzip", file: "current.json.gz"},
+		{name: "older version", file: "old.json", content: `{"schemaVersion": "0", "version": "v1.0.0"}`, wantErr: `schema version "0" is not supported, this kasba reads version 1, it was written by kasba v1.0.0`},
+		{name: "newer version", file: "new.json", content: `{"schemaVersion": "2", "version": "v2.0.0"}`, wantErr: `schema version "2" is not supported`},
+		{name: "no version", file: "none.json", content: `{"context": "prod"}`, wantErr: "written by kasba an unknown version"},
+		{name: "not JSON", file: "text.json", content: "Cluster Name: prod", wantErr: "unable to read snapshot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := ReadSnapshot(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ReadSnapshot() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.CreatedAt.Equal(data.CreatedAt) || got.Version != data.Version || got.Context != data.Context {
+				t.Errorf("ReadSnapshot() = %+v, want %+v", got, data)
+			}
+		})
+	}
+}
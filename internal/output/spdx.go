@@ -0,0 +1,192 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const SPDXVersion = "SPDX-2.3"
+
+// SPDX document types, limited to the fields kasba fills in.
+// See https://spdx.github.io/spdx-spec/v2.3/ for the full specification.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	DocumentDescribes []string           `json:"documentDescribes"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	Comment               string            `json:"comment,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+var spdxIDInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// spdxBuilder collects packages, de-duplicating them by SPDXID and linking each to the cluster package.
+type spdxBuilder struct {
+	packages      []spdxPackage
+	relationships []spdxRelationship
+	seen          map[string]bool
+}
+
+func spdxID(kind string, parts ...string) string {
+	return "SPDXRef-" + kind + "-" + strings.Trim(spdxIDInvalidChars.ReplaceAllString(strings.Join(parts, "-"), "-"), "-")
+}
+
+func (b *spdxBuilder) add(pkg spdxPackage, relationshipType string) {
+	if b.seen[pkg.SPDXID] {
+		return
+	}
+	b.seen[pkg.SPDXID] = true
+	if pkg.DownloadLocation == "" {
+		pkg.DownloadLocation = "NOASSERTION"
+	}
+	b.packages = append(b.packages, pkg)
+	b.relationships = append(b.relationships, spdxRelationship{
+		SPDXElementID:      "SPDXRef-Cluster",
+		RelationshipType:   relationshipType,
+		RelatedSPDXElement: pkg.SPDXID,
+	})
+}
+
+// addComponent adds a node level component such as the kubelet, reported as "name version" or "name://version".
+func (b *spdxBuilder) addComponent(name string, version string, purpose string) {
+	if version == "" {
+		return
+	}
+	if runtime, runtimeVersion, found := strings.Cut(version, "://"); found {
+		name, version = runtime, runtimeVersion
+	}
+	b.add(spdxPackage{
+		Name:                  name,
+		SPDXID:                spdxID("Node", name, version),
+		VersionInfo:           version,
+		PrimaryPackagePurpose: purpose,
+	}, "CONTAINS")
+}
+
+// spdxComment lists the diagnostics and incomplete sections, which tell SBOM consumers that the inventory may be
+// incomplete.
+func spdxComment(data TemplateData) string {
+	lines := data.diagnosticLines()
+	if len(data.Incomplete) > 0 {
+		lines = append(lines, "incomplete sections: "+strings.Join(data.Incomplete, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func AsSPDX(w io.Writer, data TemplateData) error {
+	b := spdxBuilder{seen: map[string]bool{}}
+	b.packages = append(b.packages, spdxPackage{
+		Name:                  data.NodeInfo.Cluster,
+		SPDXID:                "SPDXRef-Cluster",
+		DownloadLocation:      "NOASSERTION",
+		PrimaryPackagePurpose: "PLATFORM",
+		Comment:               "Kubernetes cluster assessed by kasba",
+	})
+
+	for _, item := range data.NodeInfo.Items {
+		nodeInfo := item.System
+		if nodeInfo.OSImage != "" {
+			b.add(spdxPackage{
+				Name:                  nodeInfo.OSImage,
+				SPDXID:                spdxID("OS", nodeInfo.OSImage),
+				PrimaryPackagePurpose: "OPERATING-SYSTEM",
+			}, "CONTAINS")
+		}
+		b.addComponent("kernel", nodeInfo.KernelVersion, "OPERATING-SYSTEM")
+		b.addComponent("kubelet", nodeInfo.KubeletVersion, "APPLICATION")
+		b.addComponent("kube-proxy", nodeInfo.KubeProxyVersion, "APPLICATION")
+		b.addComponent("container-runtime", nodeInfo.ContainerRuntimeVersion, "APPLICATION")
+	}
+
+	for _, item := range data.ContainerImages {
+		repository, tag, digest := imageReference(item.Image, item.Digest)
+		pkg := spdxPackage{
+			Name:                  repository,
+			SPDXID:                spdxID("Image", repository, tag, digest),
+			VersionInfo:           tag,
+			PrimaryPackagePurpose: "CONTAINER",
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  imagePURL(repository, tag, digest),
+			}},
+		}
+		if algorithm, content, found := strings.Cut(digest, ":"); found && algorithm == "sha256" {
+			pkg.Checksums = []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: content}}
+		}
+		b.add(pkg, "CONTAINS")
+	}
+
+	content, err := json.Marshal(b.packages)
+	if err != nil {
+		return err
+	}
+	timestamp := data.createdAtRFC3339()
+
+	document := spdxDocument{
+		SPDXVersion:       SPDXVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              fmt.Sprintf("kasba-%s", data.NodeInfo.Cluster),
+		DocumentNamespace: "https://kasba.io/spdx/" + uuid.NewSHA1(uuid.NameSpaceURL, append([]byte(data.NodeInfo.Cluster+timestamp), content...)).String(),
+		CreationInfo: spdxCreationInfo{
+			Created:  timestamp,
+			Creators: []string{"Tool: kasba-" + data.toolVersion()},
+			Comment:  spdxComment(data),
+		},
+		DocumentDescribes: []string{"SPDXRef-Cluster"},
+		Packages:          b.packages,
+		Relationships: append([]spdxRelationship{{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: "SPDXRef-Cluster",
+		}}, b.relationships...),
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
@@ -0,0 +1,34 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAsSPDXCreators(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    []string
+	}{
+		{"release", "v1.2.0", []string{"Tool: kasba-v1.2.0"}},
+		{"without version", "", []string{"Tool: kasba-dev"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := AsSPDX(&out, TemplateData{Version: tt.version}); err != nil {
+				t.Fatal(err)
+			}
+			var document spdxDocument
+			if err := json.Unmarshal(out.Bytes(), &document); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(document.CreationInfo.Creators, tt.want) {
+				t.Errorf("Creators = %q, want %q", document.CreationInfo.Creators, tt.want)
+			}
+		})
+	}
+}
@@ -2,16 +2,16 @@ package output
 
 import (
 	"github.com/wrkode/kasba/internal/templates"
-	"os"
+	"io"
 )
 import "text/template"
 
-func AsText(data TemplateData) error {
-	tmpl, err := template.New("as_text").Parse(templates.Text)
+func AsText(w io.Writer, data TemplateData) error {
+	tmpl, err := template.New("as_text").Funcs(FuncMap()).Parse(templates.Text)
 	if err != nil {
 		return err
 	}
-	err = tmpl.ExecuteTemplate(os.Stdout, "as_text", data)
+	err = tmpl.ExecuteTemplate(w, "as_text", data)
 	if err != nil {
 		return err
 	}
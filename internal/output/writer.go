@@ -0,0 +1,104 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"text/template"
+)
+
+// Renderer writes data in one output format.
+type Renderer func(w io.Writer, data TemplateData) error
+
+// Format is an output format selectable with --output.
+type Format struct {
+	Extension string
+	Render    Renderer
+}
+
+var Formats = map[string]Format{
+	"text":      {Extension: "txt", Render: AsText},
+	"json":      {Extension: "json", Render: AsJSON},
+	"yaml":      {Extension: "yaml", Render: AsYAML},
+	"html":      {Extension: "html", Render: AsHTML},
+	"markdown":  {Extension: "md", Render: AsMarkdown},
+	"cyclonedx": {Extension: "cdx.json", Render: AsCycloneDX},
+	"spdx":      {Extension: "spdx.json", Render: AsSPDX},
+}
+
+var fileNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// FileNameData is available to the --output-name template.
+type FileNameData struct {
+	Cluster   string // Cluster name, or the API server host if the cluster name is unknown
+	Context   string // kubeconfig context the report was collected from
+	Timestamp string // Creation time of the report as 20060102T150405Z
+	Format    string
+}
+
+// FileName expands the --output-name template for one format and appends the format's file extension.
+func FileName(pattern string, data TemplateData, format string) (string, error) {
+	tmpl, err := template.New("output_name").Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid output name: %v", err)
+	}
+
+	var timestamp string
+	if !data.CreatedAt.IsZero() {
+		timestamp = data.CreatedAt.UTC().Format("20060102T150405Z")
+	}
+
+	var name bytes.Buffer
+	err = tmpl.Execute(&name, FileNameData{
+		Cluster:   data.ClusterName(),
+		Context:   data.Context,
+		Timestamp: timestamp,
+		Format:    format,
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid output name: %v", err)
+	}
+	return SafeFileName(name.String()) + "." + Formats[format].Extension, nil
+}
+
+// SafeFileName replaces characters that are not safe in file names, such as "/" or ":", with "-".
+func SafeFileName(name string) string {
+	return fileNameInvalidChars.ReplaceAllString(name, "-")
+}
+
+// clusterNameAnnotation is set on nodes by Cluster API, and by Rancher for the clusters it provisions.
+const clusterNameAnnotation = "cluster.x-k8s.io/cluster-name"
+
+// ClusterName returns the name of the cluster: the Cluster API cluster name of the nodes, the name the distribution
+// recorded on them, e.g. by eksctl, or the kubeconfig cluster, falling back to the API server host.
+func (t TemplateData) ClusterName() string {
+	if len(t.NodeInfo.Items) > 0 && t.NodeInfo.Items[0].Annotations[clusterNameAnnotation] != "" {
+		return t.NodeInfo.Items[0].Annotations[clusterNameAnnotation]
+	}
+	if t.Distribution.ClusterName != "" {
+		return t.Distribution.ClusterName
+	}
+	if t.KubeconfigCluster != "" {
+		return t.KubeconfigCluster
+	}
+	if u, err := url.Parse(t.NodeInfo.Cluster); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return t.NodeInfo.Cluster
+}
+
+// WriteFile renders data into the file at path, replacing it if it exists.
+func WriteFile(path string, render Renderer, data TemplateData) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = render(f, data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/wrkode/kasba/internal/util"
+)
+
+func TestRenderHeader(t *testing.T) {
+	withNode := TemplateData{NodeInfo: util.NodeInfo{Items: []util.NodeItem{{
+		Name:   "n1",
+		Labels: map[string]string{"node.kubernetes.io/instance-type": "m5.large"},
+		System: v1.NodeSystemInfo{KubeletVersion: "v1.27.3"},
+	}}}}
+	renderers := map[string]Renderer{"text": AsText, "html": AsHTML, "markdown": AsMarkdown}
+
+	tests := []struct {
+		name    string
+		data    TemplateData
+		want    string
+		notWant string
+	}{
+		{"first node", withNode, "v1.27.3", ""},
+		{"instance type", withNode, "m5.large", ""},
+		{"no nodes", TemplateData{}, "Monitoring Installed", "Instance Type"},
+	}
+	for format, render := range renderers {
+		for _, tt := range tests {
+			t.Run(format+"/"+tt.name, func(t *testing.T) {
+				var out bytes.Buffer
+				if err := render(&out, tt.data); err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(out.String(), tt.want) {
+					t.Errorf("output does not contain %q", tt.want)
+				}
+				if tt.notWant != "" && strings.Contains(out.String(), tt.notWant) {
+					t.Errorf("output contains %q", tt.notWant)
+				}
+			})
+		}
+	}
+}
+
+func TestClusterName(t *testing.T) {
+	capi := util.NodeInfo{Cluster: "https://10.0.0.1:6443", Items: []util.NodeItem{{Name: "n1", Annotations: map[string]string{clusterNameAnnotation: "capi"}}}}
+	nodes := util.NodeInfo{Cluster: "https://10.0.0.1:6443", Items: []util.NodeItem{{Name: "n1"}}}
+
+	tests := []struct {
+		name string
+		data TemplateData
+		want string
+	}{
+		{"Cluster API", TemplateData{NodeInfo: capi, Distribution: util.Distribution{ClusterName: "eks"}, KubeconfigCluster: "kubeconfig"}, "capi"},
+		{"distribution", TemplateData{NodeInfo: nodes, Distribution: util.Distribution{ClusterName: "eks"}, KubeconfigCluster: "kubeconfig"}, "eks"},
+		{"kubeconfig", TemplateData{NodeInfo: nodes, KubeconfigCluster: "kubeconfig"}, "kubeconfig"},
+		{"API server host", TemplateData{NodeInfo: nodes}, "10.0.0.1"},
+		{"nothing", TemplateData{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.data.ClusterName(); got != tt.want {
+				t.Errorf("ClusterName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"io"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	YAMLAPIVersion = "kasba.io/v1alpha1"
+	YAMLKind       = "Assessment"
+)
+
+// yamlDocument wraps the assessment in a Kubernetes-style envelope so it can live next to manifests in a GitOps repo.
+// Keys are emitted in alphabetical order, which keeps apiVersion and kind at the top of the document.
+type yamlDocument struct {
+	APIVersion string       `json:"apiVersion"`
+	Kind       string       `json:"kind"`
+	Spec       TemplateData `json:"spec"`
+}
+
+func AsYAML(w io.Writer, data TemplateData) error {
+	out, err := yaml.Marshal(yamlDocument{
+		APIVersion: YAMLAPIVersion,
+		Kind:       YAMLKind,
+		Spec:       data,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
@@ -0,0 +1,482 @@
+package templates
+
+var HTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Kubernetes As-Built Assessment - KASBA</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #222; }
+header { background: #0c322c; color: #fff; padding: 1em 2em; }
+header h1 { margin: 0 0 .3em 0; font-size: 1.5em; }
+header dl { margin: 0; display: grid; grid-template-columns: max-content auto; column-gap: 1em; }
+main { display: flex; }
+nav { min-width: 14em; padding: 1em 2em; border-right: 1px solid #ddd; }
+nav ul { list-style: none; padding-left: 1em; margin: .2em 0; }
+nav > ul { padding-left: 0; }
+nav a { color: #0c322c; text-decoration: none; }
+nav a:hover { text-decoration: underline; }
+article { flex: 1; padding: 1em 2em; overflow-x: auto; }
+details { margin-bottom: 1em; }
+details > summary { font-size: 1.3em; font-weight: bold; cursor: pointer; padding: .3em 0; border-bottom: 2px solid #30ba78; }
+h3 { margin-top: 1.2em; }
+table { border-collapse: collapse; margin: .5em 0 1em 0; font-size: .9em; }
+th, td { border: 1px solid #ccc; padding: .3em .6em; text-align: left; vertical-align: top; }
+th { background: #eee; cursor: pointer; user-select: none; white-space: nowrap; }
+th.asc::after { content: " \25B2"; }
+th.desc::after { content: " \25BC"; }
+tr.bad td { background: #f8d7da; }
+tr.warn td { background: #fff3cd; }
+.errors { background: #f8d7da; border: 1px solid #e0a0a6; padding: .5em 1em; }
+.muted { color: #777; }
+</style>
+</head>
+<body>
+<header>
+<h1>Kubernetes As-Built Assessment - KASBA</h1>
+<dl>
+<dt>Date</dt><dd>{{ .CreatedAt.Format "Monday, 02-Jan-06 15:04:05 MST" }}</dd>
+<dt>Format</dt><dd>{{ .BOMFormat }}</dd>
+<dt>KASBA Version</dt><dd>{{ .Version }}</dd>
+{{- with .Distribution.Name }}
+<dt>Distribution</dt><dd>{{ . }}{{ with $.Distribution.Provider }} on {{ . }}{{ end }}</dd>
+{{- end }}
+</dl>
+</header>
+<main>
+<nav>
+<ul>
+{{- if .Diagnostics }}
+<li><a href="#diagnostics">Diagnostics</a></li>
+{{- end }}
+{{- if .Incomplete }}
+<li><a href="#incomplete">Partial Report</a></li>
+{{- end }}
+{{- if not .Diagnostics.Fatal }}
+<li><a href="#summary">Summary</a></li>
+{{- if .APIServer.GitVersion }}
+<li><a href="#api-server">API Server</a></li>
+{{- end }}
+<li><a href="#nodes">Nodes</a>
+<ul>
+{{- range $item := .NodeInfo.Items }}
+<li><a href="#node-{{ $item.Name }}">{{ $item.Name }}</a></li>
+{{- end }}
+</ul>
+</li>
+<li><a href="#workload">Workload</a></li>
+<li><a href="#storage">Storage</a>
+<ul>
+<li><a href="#storage-classes">Storage Classes</a></li>
+<li><a href="#persistent-volumes">Persistent Volumes</a></li>
+<li><a href="#persistent-volume-claims">Persistent Volume Claims</a></li>
+<li><a href="#config-maps">Config Maps</a></li>
+</ul>
+</li>
+<li><a href="#service-discovery">Service Discovery</a>
+<ul>
+<li><a href="#services">Services</a></li>
+<li><a href="#ingresses">Ingresses</a></li>
+</ul>
+</li>
+<li><a href="#rbac">RBAC and Security</a>
+<ul>
+<li><a href="#cluster-roles">Cluster Roles</a></li>
+<li><a href="#cluster-role-bindings">Cluster Role Bindings</a></li>
+<li><a href="#service-accounts">Service Accounts</a></li>
+<li><a href="#network-policies">Network Policies</a></li>
+</ul>
+</li>
+<li><a href="#container-images">Container Images</a></li>
+{{- if .ClusterVersions }}
+<li><a href="#cluster-version">Cluster Version</a></li>
+{{- end }}
+{{- if .HelmCharts }}
+<li><a href="#helm-charts">Helm Charts</a></li>
+{{- end }}
+<li><a href="#custom-resource-definitions">Custom Resource Definitions</a></li>
+{{- if .CustomResources }}
+<li><a href="#custom-resources">Custom Resources</a></li>
+{{- end }}
+{{- end }}
+</ul>
+</nav>
+<article>
+{{- if .Diagnostics }}
+<section id="diagnostics"{{ if .Diagnostics.HasErrors }} class="errors"{{ end }}>
+<h2>Diagnostics</h2>
+<table class="sortable">
+<thead><tr><th>Severity</th><th>Class</th><th>Section</th><th>Resource</th><th>Message</th><th>Time</th></tr></thead>
+<tbody>
+{{- range $diagnostic := .Diagnostics }}
+<tr class="{{ if eq $diagnostic.Severity "warning" }}warn{{ else }}bad{{ end }}"><td>{{ $diagnostic.Severity }}</td><td>{{ $diagnostic.Class }}</td><td>{{ $diagnostic.Section }}</td><td>{{ $diagnostic.Resource }}</td><td>{{ $diagnostic.Message }}</td><td>{{ $diagnostic.Timestamp.UTC.Format "2006-01-02 15:04:05Z" }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- if .Diagnostics.Fatal }}
+<p><strong>Fatal errors, quiting.</strong></p>
+{{- end }}
+</section>
+{{- end }}
+{{- if .Incomplete }}
+<section id="incomplete" class="errors">
+<h2>Partial Report</h2>
+<p>Collection was interrupted, these sections are incomplete:</p>
+<ul>
+{{- range $section := .Incomplete }}
+<li>{{ $section }}</li>
+{{- end }}
+</ul>
+</section>
+{{- end }}
+
+{{- if not .Diagnostics.Fatal }}
+<details id="summary" open>
+<summary>Summary</summary>
+<table>
+<tr><td>Cluster Name</td><td>{{ .ClusterName }}</td></tr>
+{{- with .NodeInfo.Items }}{{ $first := index . 0 }}
+<tr><td>Instance Type</td><td>{{ index $first.Labels "node.kubernetes.io/instance-type" }}</td></tr>
+{{- end }}
+<tr><td>K8s Version</td><td>{{ with .APIServer.GitVersion }}{{ . }}{{ else }}{{ with .NodeInfo.Items }}{{ (index . 0).System.KubeletVersion }}{{ end }}{{ end }}</td></tr>
+<tr><td>CNI</td><td>{{ .NetworkPlugin.Name }}{{ with .NetworkPlugin.Version }} {{ . }}{{ end }}{{ with .NetworkPlugin.Mode }} ({{ join ", " . }}){{ end }}</td></tr>
+{{- range $cni := .NetworkPlugin.Secondary }}
+<tr><td>Secondary CNI</td><td>{{ $cni.Name }}{{ with $cni.Version }} {{ . }}{{ end }}{{ with $cni.Mode }} ({{ join ", " . }}){{ end }}</td></tr>
+{{- end }}
+<tr><td>Monitoring Installed</td><td>{{ .Monitoring }}</td></tr>
+<tr><td>Longhorn installed</td><td>{{ .Longhorn }}</td></tr>
+</table>
+</details>
+
+{{- if .APIServer.GitVersion }}
+<details id="api-server" open>
+<summary>API Server</summary>
+<table>
+<tr><td>Git Version</td><td>{{ .APIServer.GitVersion }}</td></tr>
+<tr><td>Git Commit</td><td>{{ .APIServer.GitCommit }}</td></tr>
+<tr><td>Build Date</td><td>{{ .APIServer.BuildDate }}</td></tr>
+<tr><td>Go Version</td><td>{{ .APIServer.GoVersion }}</td></tr>
+<tr><td>Platform</td><td>{{ .APIServer.Platform }}</td></tr>
+</table>
+{{- if .APIServer.KubeletSkew }}
+<h3>Kubelet Version Skew</h3>
+<table class="sortable">
+<thead><tr><th>Node</th><th>Kubelet</th><th>Reason</th></tr></thead>
+<tbody>
+{{- range $skew := .APIServer.KubeletSkew }}
+<tr class="bad"><td><a href="#node-{{ $skew.Node }}">{{ $skew.Node }}</a></td><td>{{ $skew.KubeletVersion }}</td><td>{{ $skew.Reason }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+<h3>API Groups</h3>
+<table class="sortable">
+<thead><tr><th>Group Version</th><th>Preferred</th><th>Resources</th></tr></thead>
+<tbody>
+{{- range $group := .APIServer.Groups }}
+<tr><td>{{ $group.GroupVersion }}</td><td>{{ $group.Preferred }}</td><td>{{ join ", " $group.Resources }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+</details>
+
+{{- end }}
+<details id="nodes" open>
+<summary>Nodes</summary>
+<table class="sortable">
+<thead><tr><th>Node Name</th><th>Machine Name</th><th>OS Image</th><th>Arch</th><th>Kernel</th><th>Container Runtime</th><th>Kubelet</th><th>KubeProxy</th><th>Pod CIDR</th><th>CPU</th><th>Memory</th><th>Pods</th></tr></thead>
+<tbody>
+{{- range $item := .NodeInfo.Items }}
+{{- $class := "" }}
+{{- range $condition := $item.Conditions }}
+{{- $conditionClass := conditionClass $condition.Type $condition.Status }}
+{{- if eq $conditionClass "bad" }}{{ $class = "bad" }}{{ else if and (eq $conditionClass "warn") (ne $class "bad") }}{{ $class = "warn" }}{{ end }}
+{{- end }}
+<tr class="{{ $class }}">
+<td><a href="#node-{{ $item.Name }}">{{ $item.Name }}</a></td>
+<td>{{ index $item.Annotations "cluster.x-k8s.io/machine" }}</td>
+<td>{{ $item.System.OSImage }}</td>
+<td>{{ $item.System.Architecture }}</td>
+<td>{{ $item.System.KernelVersion }}</td>
+<td>{{ $item.System.ContainerRuntimeVersion }}</td>
+<td>{{ $item.System.KubeletVersion }}</td>
+<td>{{ $item.System.KubeProxyVersion }}</td>
+<td>{{ $item.PodCIDR }}</td>
+<td>{{ index $item.Allocatable "cpu" }}</td>
+<td>{{ index $item.Allocatable "memory" }}</td>
+<td>{{ index $item.Allocatable "pods" }}</td>
+</tr>
+{{- end }}
+</tbody>
+</table>
+{{- range $item := .NodeInfo.Items }}
+<h3 id="node-{{ $item.Name }}">{{ $item.Name }}</h3>
+<table>
+<tr><td>Operating System</td><td>{{ $item.System.OperatingSystem }}</td></tr>
+<tr><td>System UUID</td><td>{{ $item.System.SystemUUID }}</td></tr>
+{{- with $.Distribution.NodeArgsAnnotation }}
+<tr><td>Node Args</td><td>{{ index $item.Annotations . }}</td></tr>
+{{- end }}
+<tr><td>Pod Limits</td><td>{{ index $item.Annotations "management.cattle.io/pod-limits" }}</td></tr>
+<tr><td>Pod Requests</td><td>{{ index $item.Annotations "management.cattle.io/pod-requests" }}</td></tr>
+<tr><td>Ephemeral Storage</td><td>{{ index $item.Allocatable "ephemeral-storage" }}</td></tr>
+<tr><td>Addresses</td><td>{{ range $address := $item.Addresses }}{{ $address.Type }}: {{ $address.Address }}<br>{{ end }}</td></tr>
+<tr><td>Taints</td><td>{{ range $taint := $item.Taints }}{{ $taint.Key }}{{ with $taint.Value }}={{ . }}{{ end }}:{{ $taint.Effect }}<br>{{ end }}</td></tr>
+<tr><td>Labels</td><td>{{ range $key, $value := $item.Labels }}{{ $key }}={{ $value }}<br>{{ end }}</td></tr>
+</table>
+<table class="sortable">
+<thead><tr><th>Condition Type</th><th>Status</th><th>Reason</th><th>Message</th><th>Last Heartbeat Time</th><th>Last Transition Time</th></tr></thead>
+<tbody>
+{{- range $condition := $item.Conditions }}
+<tr class="{{ conditionClass $condition.Type $condition.Status }}">
+<td>{{ $condition.Type }}</td>
+<td>{{ $condition.Status }}</td>
+<td>{{ $condition.Reason }}</td>
+<td>{{ $condition.Message }}</td>
+<td>{{ $condition.LastHeartbeatTime }}</td>
+<td>{{ $condition.LastTransitionTime }}</td>
+</tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+</details>
+
+<details id="workload" open>
+<summary>Workload</summary>
+<table class="sortable">
+<thead><tr><th>Namespace</th><th>Type</th><th>Name</th></tr></thead>
+<tbody>
+{{- range $namespace := .WorkloadInfo.Namespaces }}
+{{- range $apptype := $namespace.WorkloadTypes }}
+{{- range $name := $apptype.Workloads }}
+<tr><td>{{ $namespace.Namespace }}</td><td>{{ $apptype.WorkloadType }}</td><td>{{ $name }}</td></tr>
+{{- end }}
+{{- end }}
+{{- end }}
+</tbody>
+</table>
+</details>
+
+<details id="storage" open>
+<summary>Storage</summary>
+<h3 id="storage-classes">Storage Classes</h3>
+<table class="sortable">
+<thead><tr><th>Name</th><th>Provisioner</th><th>Parameters</th></tr></thead>
+<tbody>
+{{- range $sc := .StorageClass }}
+<tr><td>{{ $sc.Name }}</td><td>{{ $sc.Provisioner }}</td><td>{{ range $key, $value := $sc.Parameters }}{{ $key }}: {{ $value }}<br>{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+<h3 id="persistent-volumes">Persistent Volumes</h3>
+<table class="sortable">
+<thead><tr><th>Name</th><th>Capacity</th><th>Access Modes</th><th>Reclamation Policy</th></tr></thead>
+<tbody>
+{{- range $pv := .PersistentVolumes }}
+<tr><td>{{ $pv.Name }}</td><td>{{ $pv.Size }}</td><td>{{ range $index, $mode := $pv.AccessModes }}{{ if $index }}, {{ end }}{{ $mode }}{{ end }}</td><td>{{ $pv.ReclamationPolicy }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+<h3 id="persistent-volume-claims">Persistent Volume Claims</h3>
+<table class="sortable">
+<thead><tr><th>Namespace</th><th>Name</th><th>Status</th><th>Volume</th><th>Capacity</th><th>Access Modes</th><th>Storage Class</th><th>Age</th></tr></thead>
+<tbody>
+{{- range $pvc := .PersistentVolumeClaims }}
+<tr class="{{ if ne (printf "%s" $pvc.Status) "Bound" }}warn{{ end }}"><td>{{ $pvc.Namespace }}</td><td>{{ $pvc.Name }}</td><td>{{ $pvc.Status }}</td><td>{{ $pvc.Volume }}</td><td>{{ $pvc.Capacity }}</td><td>{{ range $index, $mode := $pvc.AccessModes }}{{ if $index }}, {{ end }}{{ $mode }}{{ end }}</td><td>{{ $pvc.StorageClass }}</td><td>{{ humanizeAge $.CreatedAt $pvc.CreatedAt }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+<h3 id="config-maps">Config Maps</h3>
+<table class="sortable">
+<thead><tr><th>Namespace</th><th>Name</th><th>Age</th></tr></thead>
+<tbody>
+{{- range $cm := .ConfigMaps }}
+<tr><td>{{ $cm.Namespace }}</td><td>{{ $cm.Name }}</td><td>{{ humanizeAge $.CreatedAt $cm.CreatedAt }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+</details>
+
+<details id="service-discovery" open>
+<summary>Service Discovery</summary>
+<h3 id="services">Services</h3>
+<table class="sortable">
+<thead><tr><th>Namespace</th><th>Name</th><th>Type</th><th>ClusterIP</th><th>ExternalIP</th><th>Ports</th><th>Age</th></tr></thead>
+<tbody>
+{{- range $serviceItem := .Services }}
+<tr><td>{{ $serviceItem.Namespace }}</td><td>{{ $serviceItem.Name }}</td><td>{{ $serviceItem.Type }}</td><td>{{ $serviceItem.ClusterIP }}</td><td>{{ if $serviceItem.ExternalIP }}{{ $serviceItem.ExternalIP }}{{ else }}<span class="muted">&lt;none&gt;</span>{{ end }}</td>
+<td>{{ range $port := $serviceItem.Ports }}{{ if $port.Name }}{{ $port.Name }}: {{ end }}{{ $port.Port }}/{{ $port.Protocol }}{{ if $port.NodePort }} (NodePort: {{ $port.NodePort }}){{ end }}<br>{{ end }}</td><td>{{ humanizeAge $.CreatedAt $serviceItem.CreatedAt }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+<h3 id="ingresses">Ingresses</h3>
+<table class="sortable">
+<thead><tr><th>Namespace</th><th>Name</th><th>Hosts</th><th>Default Backend</th><th>Addresses</th><th>Age</th></tr></thead>
+<tbody>
+{{- range $ingressItem := .Ingresses }}
+<tr><td>{{ $ingressItem.Namespace }}</td><td>{{ $ingressItem.Name }}</td>
+<td>{{ range $host := $ingressItem.Hosts }}{{ $host.Host }}{{ range $path := $host.Paths }} {{ $path }}{{ end }}<br>{{ end }}</td>
+<td>{{ with $ingressItem.DefaultBackend.ServiceName }}{{ . }}:{{ $ingressItem.DefaultBackend.ServicePort }}{{ end }}</td>
+<td>{{ range $address := $ingressItem.Addresses }}{{ $address }}<br>{{ end }}</td><td>{{ humanizeAge $.CreatedAt $ingressItem.CreatedAt }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+</details>
+
+<details id="rbac" open>
+<summary>RBAC and Security</summary>
+<h3 id="cluster-roles">Cluster Roles</h3>
+<table class="sortable">
+<thead><tr><th>Role Name</th><th>Verbs</th></tr></thead>
+<tbody>
+{{- range $roleItem := .ClusterRoles }}
+<tr><td>{{ $roleItem.Name }}</td><td>{{ range $index, $verb := $roleItem.Verbs }}{{ if $index }}, {{ end }}{{ $verb }}{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+<h3 id="cluster-role-bindings">Cluster Role Bindings</h3>
+<table class="sortable">
+<thead><tr><th>CRB Name</th><th>Role Name</th><th>Subjects</th></tr></thead>
+<tbody>
+{{- range $crbItem := .ClusterRoleBindings }}
+<tr><td>{{ $crbItem.Name }}</td><td>{{ $crbItem.RoleName }}</td>
+<td>{{ range $subject := $crbItem.Subjects }}{{ $subject.Kind }} {{ if $subject.Namespace }}{{ $subject.Namespace }}/{{ end }}{{ $subject.Name }}<br>{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+<h3 id="service-accounts">Service Accounts</h3>
+<table class="sortable">
+<thead><tr><th>Namespace</th><th>SA Name</th><th>Secrets</th><th>Age</th></tr></thead>
+<tbody>
+{{- range $saItem := .ServiceAccounts }}
+<tr><td>{{ $saItem.Namespace }}</td><td>{{ $saItem.Name }}</td><td>{{ $saItem.Secrets }}</td><td>{{ humanizeAge $.CreatedAt $saItem.CreatedAt }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+<h3 id="network-policies">Network Policies</h3>
+<table class="sortable">
+<thead><tr><th>Namespace</th><th>Name</th><th>Pod Selector</th><th>Policy Types</th><th>Ingress Rules</th><th>Egress Rules</th><th>Age</th></tr></thead>
+<tbody>
+{{- range $netPolItem := .NetworkPolicies }}
+<tr><td>{{ $netPolItem.Namespace }}</td><td>{{ $netPolItem.Name }}</td><td>{{ $netPolItem.PodSelector.MatchLabels }}</td>
+<td>{{ range $index, $type := $netPolItem.PolicyTypes }}{{ if $index }}, {{ end }}{{ $type }}{{ end }}</td>
+<td>{{ len $netPolItem.Ingress }}</td><td>{{ len $netPolItem.Egress }}</td><td>{{ humanizeAge $.CreatedAt $netPolItem.CreatedAt }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+</details>
+
+<details id="container-images" open>
+<summary>Container Images</summary>
+<table class="sortable">
+<thead><tr><th>Image</th><th>Digest</th><th>Namespace</th><th>Workload</th><th>Container</th></tr></thead>
+<tbody>
+{{- range $imageItem := .ContainerImages }}
+<tr><td>{{ $imageItem.Image }}</td><td>{{ $imageItem.Digest }}</td><td>{{ $imageItem.Namespace }}</td><td>{{ $imageItem.OwnerKind }}/{{ $imageItem.OwnerName }}</td><td>{{ $imageItem.Container }}{{ if $imageItem.InitContainer }} (init){{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+</details>
+{{- if .ClusterVersions }}
+
+<details id="cluster-version" open>
+<summary>Cluster Version</summary>
+{{- range $clusterVersion := .ClusterVersions }}
+<table>
+<tr><td>Version</td><td>{{ $clusterVersion.Version }}</td></tr>
+<tr><td>Channel</td><td>{{ $clusterVersion.Channel }}</td></tr>
+<tr><td>Cluster ID</td><td>{{ $clusterVersion.ClusterID }}</td></tr>
+</table>
+<h3>Update History</h3>
+<table>
+<thead><tr><th>Version</th><th>State</th><th>Started</th></tr></thead>
+<tbody>
+{{- range $update := $clusterVersion.History }}
+<tr><td>{{ $update.Version }}</td><td>{{ $update.State }}</td><td>{{ $update.StartedTime }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+</details>
+{{- end }}
+{{- if .HelmCharts }}
+
+<details id="helm-charts" open>
+<summary>Helm Charts</summary>
+<table class="sortable">
+<thead><tr><th>Namespace</th><th>Name</th><th>Chart</th><th>Version</th><th>Repository</th><th>Target Namespace</th></tr></thead>
+<tbody>
+{{- range $chart := .HelmCharts }}
+<tr><td>{{ $chart.Namespace }}</td><td>{{ $chart.Name }}</td><td>{{ $chart.Chart }}</td><td>{{ $chart.Version }}</td><td>{{ $chart.Repo }}</td><td>{{ $chart.TargetNamespace }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+</details>
+{{- end }}
+
+<details id="custom-resource-definitions" open>
+<summary>Custom Resource Definitions</summary>
+<table class="sortable">
+<thead><tr><th>Name</th><th>Group</th><th>Kind</th><th>Scope</th><th>Versions</th><th>Stored Versions</th><th>Instances</th><th>Per Namespace</th></tr></thead>
+<tbody>
+{{- range $crd := .CustomResourceDefinitions }}
+<tr><td>{{ $crd.Name }}</td><td>{{ $crd.Group }}</td><td>{{ $crd.Kind }}</td><td>{{ $crd.Scope }}</td>
+<td>{{ range $i, $version := $crd.Versions }}{{ if $i }}, {{ end }}{{ $version }}{{ end }}</td>
+<td>{{ range $i, $version := $crd.StoredVersions }}{{ if $i }}, {{ end }}{{ $version }}{{ end }}</td>
+<td>{{ with $crd.Total }}{{ . }}{{ else }}unknown{{ end }}</td>
+<td>{{ range $count := $crd.Instances }}{{ with $count.Namespace }}{{ . }}: {{ $count.Count }}<br>{{ end }}{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+</details>
+{{- if .CustomResources }}
+
+<details id="custom-resources" open>
+<summary>Custom Resources</summary>
+{{- range $dump := .CustomResources }}
+<h3>{{ $dump.Kind }} <small>{{ $dump.Resource }}{{ with $dump.Group }}.{{ . }}{{ end }}/{{ $dump.Version }}</small></h3>
+<table class="sortable">
+<thead><tr><th>Namespace</th><th>Name</th></tr></thead>
+<tbody>
+{{- range $object := $dump.Items }}{{ with index $object "metadata" }}
+<tr><td>{{ with index . "namespace" }}{{ . }}{{ end }}</td><td>{{ index . "name" }}</td></tr>
+{{- end }}{{ end }}
+</tbody>
+</table>
+{{- end }}
+</details>
+{{- end }}
+{{- end }}
+</article>
+</main>
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, column) {
+    th.addEventListener("click", function () {
+      var body = table.tBodies[0];
+      var ascending = !th.classList.contains("asc");
+      table.querySelectorAll("th").forEach(function (other) { other.classList.remove("asc", "desc"); });
+      th.classList.add(ascending ? "asc" : "desc");
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[column].textContent.trim(), y = b.cells[column].textContent.trim();
+        var result = (x !== "" && y !== "" && !isNaN(x) && !isNaN(y)) ? x - y : x.localeCompare(y, undefined, {numeric: true});
+        return ascending ? result : -result;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+document.querySelectorAll("nav a").forEach(function (link) {
+  link.addEventListener("click", function () {
+    var target = document.querySelector(link.getAttribute("href"));
+    for (var el = target; el; el = el.parentElement) {
+      if (el.tagName === "DETAILS") { el.open = true; }
+    }
+  });
+});
+</script>
+</body>
+</html>
+`
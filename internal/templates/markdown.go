@@ -0,0 +1,256 @@
+package templates
+
+var Markdown = `# Kubernetes As-Built Assessment - KASBA
+
+| | |
+|---|---|
+| Date | {{ .CreatedAt.Format "Monday, 02-Jan-06 15:04:05 MST" }} |
+| Format | {{ cell .BOMFormat }} |
+| KASBA Version | {{ cell .Version }} |
+{{- with .Distribution.Name }}
+| Distribution | {{ cell . }}{{ with $.Distribution.Provider }} on {{ cell . }}{{ end }} |
+{{- end }}
+{{- if .Diagnostics }}
+
+## Diagnostics
+
+| Severity | Class | Section | Resource | Message | Time |
+|---|---|---|---|---|---|
+{{- range $diagnostic := .Diagnostics }}
+| {{ $diagnostic.Severity }} | {{ $diagnostic.Class }} | {{ cell $diagnostic.Section }} | {{ cell $diagnostic.Resource }} | {{ cell $diagnostic.Message }} | {{ $diagnostic.Timestamp.UTC.Format "2006-01-02 15:04:05Z" }} |
+{{- end }}
+{{- end }}
+{{- if .Diagnostics.Fatal }}
+
+**Fatal errors, quiting.**
+{{- end }}
+{{- if .Incomplete }}
+
+## Partial Report
+
+Collection was interrupted, these sections are incomplete:
+{{ range $section := .Incomplete }}
+- {{ cell $section }}
+{{- end }}
+{{- end }}
+{{- if not .Diagnostics.Fatal }}
+
+## Summary
+
+| | |
+|---|---|
+| Cluster Name | {{ cell .ClusterName }} |
+{{- with .NodeInfo.Items }}{{ $first := index . 0 }}
+| Instance Type | {{ cell (index $first.Labels "node.kubernetes.io/instance-type") }} |
+{{- end }}
+| K8s Version | {{ with .APIServer.GitVersion }}{{ cell . }}{{ else }}{{ with .NodeInfo.Items }}{{ cell (index . 0).System.KubeletVersion }}{{ end }}{{ end }} |
+| CNI | {{ cell .NetworkPlugin.Name }}{{ with .NetworkPlugin.Version }} {{ cell . }}{{ end }}{{ with .NetworkPlugin.Mode }} ({{ cell (join ", " .) }}){{ end }} |
+{{- range $cni := .NetworkPlugin.Secondary }}
+| Secondary CNI | {{ cell $cni.Name }}{{ with $cni.Version }} {{ cell . }}{{ end }}{{ with $cni.Mode }} ({{ cell (join ", " .) }}){{ end }} |
+{{- end }}
+| Monitoring Installed | {{ .Monitoring }} |
+| Longhorn installed | {{ .Longhorn }} |
+{{- if .APIServer.GitVersion }}
+
+## API Server
+
+| | |
+|---|---|
+| Git Version | {{ cell .APIServer.GitVersion }} |
+| Git Commit | {{ cell .APIServer.GitCommit }} |
+| Build Date | {{ cell .APIServer.BuildDate }} |
+| Go Version | {{ cell .APIServer.GoVersion }} |
+| Platform | {{ cell .APIServer.Platform }} |
+{{- if .APIServer.KubeletSkew }}
+
+### Kubelet Version Skew
+
+| Node | Kubelet | Reason |
+|---|---|---|
+{{- range $skew := .APIServer.KubeletSkew }}
+| {{ cell $skew.Node }} | {{ cell $skew.KubeletVersion }} | {{ cell $skew.Reason }} |
+{{- end }}
+{{- end }}
+
+### API Groups
+
+| Group Version | Preferred | Resources |
+|---|---|---|
+{{- range $group := .APIServer.Groups }}
+| {{ cell $group.GroupVersion }} | {{ $group.Preferred }} | {{ cell (join ", " $group.Resources) }} |
+{{- end }}
+{{- end }}
+
+## Nodes
+
+| Node Name | Machine Name | OS Image | Arch | Kernel | Container Runtime | Kubelet | KubeProxy | Pod CIDR | CPU | Memory | Pods |
+|---|---|---|---|---|---|---|---|---|---|---|---|
+{{- range $item := .NodeInfo.Items }}
+| {{ cell $item.Name }} | {{ cell (index $item.Annotations "cluster.x-k8s.io/machine") }} | {{ cell $item.System.OSImage }} | {{ cell $item.System.Architecture }} | {{ cell $item.System.KernelVersion }} | {{ cell $item.System.ContainerRuntimeVersion }} | {{ cell $item.System.KubeletVersion }} | {{ cell $item.System.KubeProxyVersion }} | {{ cell $item.PodCIDR }} | {{ cell (index $item.Allocatable "cpu") }} | {{ cell (index $item.Allocatable "memory") }} | {{ cell (index $item.Allocatable "pods") }} |
+{{- end }}
+{{ range $item := .NodeInfo.Items }}
+### Node {{ $item.Name }}
+
+| | |
+|---|---|
+| Addresses | {{ range $i, $address := $item.Addresses }}{{ if $i }}<br>{{ end }}{{ $address.Type }}: {{ cell $address.Address }}{{ end }} |
+| Taints | {{ range $i, $taint := $item.Taints }}{{ if $i }}<br>{{ end }}{{ cell $taint.Key }}{{ with $taint.Value }}={{ cell . }}{{ end }}:{{ $taint.Effect }}{{ end }} |
+| Labels | {{ range $key, $value := $item.Labels }}{{ cell $key }}={{ cell $value }}<br>{{ end }} |
+
+#### Conditions
+
+| Condition Type | Status | Reason | Message | Last Transition Time |
+|---|---|---|---|---|
+{{- range $condition := $item.Conditions }}
+| {{ cell $condition.Type }} | {{ cell $condition.Status }} | {{ cell $condition.Reason }} | {{ cell $condition.Message }} | {{ cell $condition.LastTransitionTime }} |
+{{- end }}
+{{ end }}
+## Workload
+
+| Namespace | Type | Name |
+|---|---|---|
+{{- range $namespace := .WorkloadInfo.Namespaces }}
+{{- range $apptype := $namespace.WorkloadTypes }}
+{{- range $name := $apptype.Workloads }}
+| {{ cell $namespace.Namespace }} | {{ cell $apptype.WorkloadType }} | {{ cell $name }} |
+{{- end }}
+{{- end }}
+{{- end }}
+
+## Storage
+
+### Storage Classes
+
+| Name | Provisioner | Parameters |
+|---|---|---|
+{{- range $sc := .StorageClass }}
+| {{ cell $sc.Name }} | {{ cell $sc.Provisioner }} | {{ range $key, $value := $sc.Parameters }}{{ cell $key }}: {{ cell $value }}<br>{{ end }} |
+{{- end }}
+
+### Persistent Volumes
+
+| Name | Capacity | Access Modes | Reclamation Policy |
+|---|---|---|---|
+{{- range $pv := .PersistentVolumes }}
+| {{ cell $pv.Name }} | {{ cell $pv.Size }} | {{ range $index, $mode := $pv.AccessModes }}{{ if $index }}, {{ end }}{{ $mode }}{{ end }} | {{ cell $pv.ReclamationPolicy }} |
+{{- end }}
+
+### Persistent Volume Claims
+
+| Namespace | Name | Status | Volume | Capacity | Access Modes | Storage Class | Age |
+|---|---|---|---|---|---|---|---|
+{{- range $pvc := .PersistentVolumeClaims }}
+| {{ cell $pvc.Namespace }} | {{ cell $pvc.Name }} | {{ cell $pvc.Status }} | {{ cell $pvc.Volume }} | {{ cell $pvc.Capacity }} | {{ range $index, $mode := $pvc.AccessModes }}{{ if $index }}, {{ end }}{{ $mode }}{{ end }} | {{ cell $pvc.StorageClass }} | {{ humanizeAge $.CreatedAt $pvc.CreatedAt }} |
+{{- end }}
+
+## Service Discovery
+
+### Services
+
+| Namespace | Name | Type | ClusterIP | ExternalIP | Ports | Age |
+|---|---|---|---|---|---|---|
+{{- range $serviceItem := .Services }}
+| {{ cell $serviceItem.Namespace }} | {{ cell $serviceItem.Name }} | {{ cell $serviceItem.Type }} | {{ cell $serviceItem.ClusterIP }} | {{ if $serviceItem.ExternalIP }}{{ cell $serviceItem.ExternalIP }}{{ else }}&lt;none&gt;{{ end }} | {{ range $index, $port := $serviceItem.Ports }}{{ if $index }}<br>{{ end }}{{ if $port.Name }}{{ cell $port.Name }}: {{ end }}{{ $port.Port }}/{{ $port.Protocol }}{{ if $port.NodePort }} (NodePort: {{ $port.NodePort }}){{ end }}{{ end }} | {{ humanizeAge $.CreatedAt $serviceItem.CreatedAt }} |
+{{- end }}
+
+### Ingresses
+
+| Namespace | Name | Hosts | Default Backend | Addresses | Age |
+|---|---|---|---|---|---|
+{{- range $ingressItem := .Ingresses }}
+| {{ cell $ingressItem.Namespace }} | {{ cell $ingressItem.Name }} | {{ range $index, $host := $ingressItem.Hosts }}{{ if $index }}<br>{{ end }}{{ cell $host.Host }}{{ range $path := $host.Paths }} {{ cell $path }}{{ end }}{{ end }} | {{ with $ingressItem.DefaultBackend.ServiceName }}{{ cell . }}:{{ cell $ingressItem.DefaultBackend.ServicePort }}{{ end }} | {{ range $index, $address := $ingressItem.Addresses }}{{ if $index }}<br>{{ end }}{{ cell $address }}{{ end }} | {{ humanizeAge $.CreatedAt $ingressItem.CreatedAt }} |
+{{- end }}
+
+## RBAC and Security
+
+### Cluster Roles
+
+| Role Name | Verbs |
+|---|---|
+{{- range $roleItem := .ClusterRoles }}
+| {{ cell $roleItem.Name }} | {{ range $index, $verb := $roleItem.Verbs }}{{ if $index }}, {{ end }}{{ cell $verb }}{{ end }} |
+{{- end }}
+
+### Cluster Role Bindings
+
+| CRB Name | Role Name | Subjects |
+|---|---|---|
+{{- range $crbItem := .ClusterRoleBindings }}
+| {{ cell $crbItem.Name }} | {{ cell $crbItem.RoleName }} | {{ range $index, $subject := $crbItem.Subjects }}{{ if $index }}<br>{{ end }}{{ $subject.Kind }} {{ if $subject.Namespace }}{{ cell $subject.Namespace }}/{{ end }}{{ cell $subject.Name }}{{ end }} |
+{{- end }}
+
+### Service Accounts
+
+| Namespace | SA Name | Secrets | Age |
+|---|---|---|---|
+{{- range $saItem := .ServiceAccounts }}
+| {{ cell $saItem.Namespace }} | {{ cell $saItem.Name }} | {{ $saItem.Secrets }} | {{ humanizeAge $.CreatedAt $saItem.CreatedAt }} |
+{{- end }}
+
+### Network Policies
+
+| Namespace | Name | Pod Selector | Policy Types | Ingress Rules | Egress Rules | Age |
+|---|---|---|---|---|---|---|
+{{- range $netPolItem := .NetworkPolicies }}
+| {{ cell $netPolItem.Namespace }} | {{ cell $netPolItem.Name }} | {{ cell $netPolItem.PodSelector.MatchLabels }} | {{ range $index, $type := $netPolItem.PolicyTypes }}{{ if $index }}, {{ end }}{{ $type }}{{ end }} | {{ len $netPolItem.Ingress }} | {{ len $netPolItem.Egress }} | {{ humanizeAge $.CreatedAt $netPolItem.CreatedAt }} |
+{{- end }}
+
+## Container Images
+
+| Image | Digest | Namespace | Workload | Container |
+|---|---|---|---|---|
+{{- range $imageItem := .ContainerImages }}
+| {{ cell $imageItem.Image }} | {{ cell $imageItem.Digest }} | {{ cell $imageItem.Namespace }} | {{ cell $imageItem.OwnerKind }}/{{ cell $imageItem.OwnerName }} | {{ cell $imageItem.Container }}{{ if $imageItem.InitContainer }} (init){{ end }} |
+{{- end }}
+{{- range $clusterVersion := .ClusterVersions }}
+
+## Cluster Version
+
+| | |
+|---|---|
+| Version | {{ cell $clusterVersion.Version }} |
+| Channel | {{ cell $clusterVersion.Channel }} |
+| Cluster ID | {{ cell $clusterVersion.ClusterID }} |
+
+### Update History
+
+| Version | State | Started |
+|---|---|---|
+{{- range $update := $clusterVersion.History }}
+| {{ cell $update.Version }} | {{ cell $update.State }} | {{ cell $update.StartedTime }} |
+{{- end }}
+{{- end }}
+{{- if .HelmCharts }}
+
+## Helm Charts
+
+| Namespace | Name | Chart | Version | Repository | Target Namespace |
+|---|---|---|---|---|---|
+{{- range $chart := .HelmCharts }}
+| {{ cell $chart.Namespace }} | {{ cell $chart.Name }} | {{ cell $chart.Chart }} | {{ cell $chart.Version }} | {{ cell $chart.Repo }} | {{ cell $chart.TargetNamespace }} |
+{{- end }}
+{{- end }}
+
+## Custom Resource Definitions
+
+| Name | Group | Kind | Scope | Versions | Stored Versions | Instances | Per Namespace |
+|---|---|---|---|---|---|---|---|
+{{- range $crd := .CustomResourceDefinitions }}
+| {{ cell $crd.Name }} | {{ cell $crd.Group }} | {{ cell $crd.Kind }} | {{ cell $crd.Scope }} | {{ range $i, $version := $crd.Versions }}{{ if $i }}, {{ end }}{{ cell $version }}{{ end }} | {{ range $i, $version := $crd.StoredVersions }}{{ if $i }}, {{ end }}{{ cell $version }}{{ end }} | {{ with $crd.Total }}{{ . }}{{ else }}unknown{{ end }} | {{ range $i, $count := $crd.Instances }}{{ with $count.Namespace }}{{ if $i }}<br>{{ end }}{{ cell . }}: {{ $count.Count }}{{ end }}{{ end }} |
+{{- end }}
+{{- if .CustomResources }}
+
+## Custom Resources
+{{- range $dump := .CustomResources }}
+
+### {{ $dump.Kind }} ({{ $dump.Resource }}{{ with $dump.Group }}.{{ . }}{{ end }}/{{ $dump.Version }})
+
+| Namespace | Name |
+|---|---|
+{{- range $object := $dump.Items }}{{ with index $object "metadata" }}
+| {{ with index . "namespace" }}{{ cell . }}{{ end }} | {{ cell (index . "name") }} |
+{{- end }}{{ end }}
+{{- end }}
+{{- end }}
+{{- end }}
+`
@@ -3,55 +3,94 @@ package templates
 var Text = `
 #####################################################################
 Kubernetes As-Built Assessment - KASBA
-Date: 		   {{ .CreatedAt }}
+Date: 		   {{ .CreatedAt.Format "Monday, 02-Jan-06 15:04:05 MST" }}
 Format: 	   {{ .BOMFormat }}
 KASBA Version: {{ .Version }}
+Context:       {{ .Context }}
+{{- with .Distribution.Name }}
+Distribution:  {{ . }}{{ with $.Distribution.Provider }} on {{ . }}{{ end }}
+{{- end }}
 #####################################################################
 
-{{ if .Errors.HasErrors -}}
-Errors:
-  {{ range $index, $error := .Errors.Errors -}}
-{{ $error }}
+{{ if .Diagnostics -}}
+Diagnostics:
+{{ range $diagnostic := .Diagnostics -}}
+{{ "  " }}[{{ $diagnostic.Severity }}/{{ $diagnostic.Class }}] {{ with $diagnostic.Section }}{{ . }}: {{ end }}{{ $diagnostic.Message }}{{ with $diagnostic.Resource }} (resource {{ . }}){{ end }}
 {{ end -}}
 {{ end }}
 
-{{ if .Errors.Fatal }}
+{{ if .Diagnostics.Fatal }}
 Fatal errors, quiting.
 {{ end }}
+{{ if .Incomplete -}}
+PARTIAL REPORT - collection was interrupted, these sections are incomplete:
+{{ range $section := .Incomplete }}  {{ $section }}
+{{ end }}{{ end }}
 
-{{ if not .Errors.Fatal -}}
+{{ if not .Diagnostics.Fatal -}}
 
-Cluster Name:         {{ (index .NodeInfo.Items 0).Metadata.Annotations.ClusterXK8SIoClusterName }}
-Instance Type:        {{ (index .NodeInfo.Items 0).Metadata.Labels.NodeKubernetesIoInstanceType }}
-K8s Version:          {{ (index .NodeInfo.Items 0).Status.NodeInfo.KubeletVersion }}
+Cluster Name:         {{ .ClusterName }}
+{{ with .NodeInfo.Items }}{{ $first := index . 0 -}}
+Instance Type:        {{ index $first.Labels "node.kubernetes.io/instance-type" }}
+{{ end -}}
+K8s Version:          {{ with .APIServer.GitVersion }}{{ . }}{{ else }}{{ with .NodeInfo.Items }}{{ (index . 0).System.KubeletVersion }}{{ end }}{{ end }}
 
-CNI:                  {{ .NetworkPlugin }}
+CNI:                  {{ .NetworkPlugin.Name }}{{ with .NetworkPlugin.Version }} {{ . }}{{ end }}{{ with .NetworkPlugin.Mode }} ({{ join ", " . }}){{ end }}
+{{- range $cni := .NetworkPlugin.Secondary }}
+Secondary CNI:        {{ $cni.Name }}{{ with $cni.Version }} {{ . }}{{ end }}{{ with $cni.Mode }} ({{ join ", " . }}){{ end }}
+{{- end }}
 Monitoring Installed: {{ .Monitoring }}
 Longhorn installed:   {{ .Longhorn }}
+{{- if .APIServer.GitVersion }}
+
+--- API Server ---
+Git Version:          {{ .APIServer.GitVersion }}
+Git Commit:           {{ .APIServer.GitCommit }}
+Build Date:           {{ .APIServer.BuildDate }}
+Go Version:           {{ .APIServer.GoVersion }}
+Platform:             {{ .APIServer.Platform }}
+{{- if .APIServer.KubeletSkew }}
+Kubelet Version Skew:
+{{- range $skew := .APIServer.KubeletSkew }}
+  {{ $skew.Node }} ({{ $skew.KubeletVersion }}): {{ $skew.Reason }}
+{{- end }}
+{{- end }}
+API Groups:
+{{- range $group := .APIServer.Groups }}
+  {{ $group.GroupVersion }}{{ if $group.Preferred }} (preferred){{ end }}: {{ join ", " $group.Resources }}
+{{- end }}
+{{- end }}
 
 {{ range $index, $item := .NodeInfo.Items }}
-Cluster Machine Name: {{ $item.Metadata.Annotations.ClusterXK8SIoMachine }}
-Cluster Node Name:    {{ $item.Metadata.Name }}
-Operating System:     {{ $item.Status.NodeInfo.OperatingSystem }}
-OS Image:             {{ $item.Status.NodeInfo.OsImage }}
-Node Arch:            {{ $item.Status.NodeInfo.Architecture }}
-Kernel Version:       {{ $item.Status.NodeInfo.KernelVersion }}
-System UUID:          {{ $item.Status.NodeInfo.SystemUUID }}
-Container Runtime:    {{ $item.Status.NodeInfo.ContainerRuntimeVersion }}
-Kube Version:         {{ $item.Status.NodeInfo.KubeletVersion }}
-KubeProxy Version:    {{ $item.Status.NodeInfo.KubeProxyVersion }}
-Node Args:            {{ $item.Metadata.Annotations.Rke2IoNodeArgs }}
-Pod CIDR:             {{ $item.Spec.PodCIDR }}
-Pod Limits:           {{ $item.Metadata.Annotations.ManagementCattleIoPodLimits }}
-Pod Requests:         {{ $item.Metadata.Annotations.ManagementCattleIoPodRequests }}
+Cluster Machine Name: {{ index $item.Annotations "cluster.x-k8s.io/machine" }}
+Cluster Node Name:    {{ $item.Name }}
+Operating System:     {{ $item.System.OperatingSystem }}
+OS Image:             {{ $item.System.OSImage }}
+Node Arch:            {{ $item.System.Architecture }}
+Kernel Version:       {{ $item.System.KernelVersion }}
+System UUID:          {{ $item.System.SystemUUID }}
+Container Runtime:    {{ $item.System.ContainerRuntimeVersion }}
+Kube Version:         {{ $item.System.KubeletVersion }}
+KubeProxy Version:    {{ $item.System.KubeProxyVersion }}
+{{- with $.Distribution.NodeArgsAnnotation }}
+Node Args:            {{ index $item.Annotations . }}
+{{- end }}
+Pod CIDR:             {{ $item.PodCIDR }}
+Addresses:            {{ range $i, $address := $item.Addresses }}{{ if $i }}, {{ end }}{{ $address.Type }}={{ $address.Address }}{{ end }}
+Taints:               {{ range $i, $taint := $item.Taints }}{{ if $i }}, {{ end }}{{ $taint.Key }}{{ with $taint.Value }}={{ . }}{{ end }}:{{ $taint.Effect }}{{ end }}
+Pod Limits:           {{ index $item.Annotations "management.cattle.io/pod-limits" }}
+Pod Requests:         {{ index $item.Annotations "management.cattle.io/pod-requests" }}
 --- Allocatable ---
-CPU:                  {{ $item.Status.Allocatable.CPU }}
-Memory:               {{ $item.Status.Allocatable.Memory }}
-Ephemeral Storage:    {{ $item.Status.Allocatable.EphemeralStorage }}
-Pods:                 {{ $item.Status.Allocatable.Pods }}
+CPU:                  {{ index $item.Allocatable "cpu" }}
+Memory:               {{ index $item.Allocatable "memory" }}
+Ephemeral Storage:    {{ index $item.Allocatable "ephemeral-storage" }}
+Pods:                 {{ index $item.Allocatable "pods" }}
+--- Labels ---
+{{ range $key, $value := $item.Labels }}{{ $key }}={{ $value }}
+{{ end -}}
 
---- Messages {{ $item.Metadata.Name }}---
-{{ range $index, $condition := $item.Status.Conditions -}}
+--- Messages {{ $item.Name }}---
+{{ range $index, $condition := $item.Conditions -}}
 	Condition Type: {{ $condition.Type }}
 	  Last Heartbeat Time:  {{ $condition.LastHeartbeatTime }}
 	  Last Transition Time: {{ $condition.LastTransitionTime }}
@@ -105,28 +144,23 @@ Namespace: {{ $namespace.Namespace }}
       Capacity: {{ $pvc.Capacity }}
       AccessModes: {{ $pvc.AccessModes }}
       StorageClass: {{ $pvc.StorageClass }}
-      Age: {{ $pvc.Age }}
+      Age: {{ humanizeAge $.CreatedAt $pvc.CreatedAt }}
   {{- end }}
 
   Config Maps:
-{{- $currentNamespace := "" -}}
-{{- range $index, $cm := .ConfigMaps -}}
-{{- if ne $cm.Namespace $currentNamespace }}
-Namespace: {{ $cm.Namespace }}
-{{- $currentNamespace = $cm.Namespace -}}
-{{- end }}
+{{- range $group := groupBy "Namespace" .ConfigMaps }}
+Namespace: {{ $group.Key }}
+{{- range $index, $cm := $group.Items }}
   Name:      {{ $cm.Name }}
-  Age:       {{ $cm.Age }}
+  Age:       {{ humanizeAge $.CreatedAt $cm.CreatedAt }}
+{{- end }}
 {{- end }}
 
 --- Service Discovery ---
   Services:
-{{- $currentNamespace := "" -}}
-{{- range $index, $serviceItem := .Services -}}
-{{- if ne $serviceItem.Namespace $currentNamespace }}
-Namespace: {{ $serviceItem.Namespace }}
-{{- $currentNamespace = $serviceItem.Namespace -}}
-{{- end }}
+{{- range $group := groupBy "Namespace" .Services }}
+Namespace: {{ $group.Key }}
+{{- range $index, $serviceItem := $group.Items }}
   Name:      {{ $serviceItem.Name }}
     Type:      {{ $serviceItem.Type }}
     ClusterIP: {{ $serviceItem.ClusterIP }}
@@ -135,16 +169,14 @@ Namespace: {{ $serviceItem.Namespace }}
     {{- range $pIndex, $port := $serviceItem.Ports }}
       {{ $port.Name }}: {{ $port.Port }}/{{ $port.Protocol }} {{ if $port.NodePort }}(NodePort: {{ $port.NodePort }}){{ end }}
     {{- end }}
-    Age:       {{ $serviceItem.Age }}d
+    Age:       {{ humanizeAge $.CreatedAt $serviceItem.CreatedAt }}
+{{- end }}
 {{- end }}
 
 Ingresses:
-{{- $currentNamespace := "" -}}
-{{- range $index, $ingressItem := .Ingresses -}}
-{{- if ne $ingressItem.Namespace $currentNamespace }}
-Namespace: {{ $ingressItem.Namespace }}
-{{- $currentNamespace = $ingressItem.Namespace -}}
-{{- end }}
+{{- range $group := groupBy "Namespace" .Ingresses }}
+Namespace: {{ $group.Key }}
+{{- range $index, $ingressItem := $group.Items }}
     Name: {{ $ingressItem.Name }}
       Hosts: 
       {{- range $hostIndex, $host := $ingressItem.Hosts }}
@@ -159,7 +191,8 @@ Namespace: {{ $ingressItem.Namespace }}
       {{- range $addressIndex, $address := $ingressItem.Addresses}}
       - {{ $address }}
       {{- end }}
-      Age: {{ $ingressItem.Age }}d
+      Age: {{ humanizeAge $.CreatedAt $ingressItem.CreatedAt }}
+{{- end }}
 {{- end }}
 
 --- RBAC and Security ---
@@ -184,24 +217,19 @@ CRB Name: {{ $crbItem.Name }}
 {{- end }}
 
 Service Accounts:
-{{- $currentNamespace := "" -}}
-{{- range $index, $saItem := .ServiceAccounts -}}
-{{- if ne $saItem.Namespace $currentNamespace }}
-Namespace: {{ $saItem.Namespace }}
-{{- $currentNamespace = $saItem.Namespace -}}
-{{- end }}
+{{- range $group := groupBy "Namespace" .ServiceAccounts }}
+Namespace: {{ $group.Key }}
+{{- range $index, $saItem := $group.Items }}
     SA Name: {{ $saItem.Name }}
       Secrets: {{ $saItem.Secrets }}
-      Age: {{ $saItem.Age }}
+      Age: {{ humanizeAge $.CreatedAt $saItem.CreatedAt }}
+{{- end }}
 {{- end }}
 
 Network Policies:
-{{- $currentNamespace := "" -}}
-{{- range $index, $netPolItem := .NetworkPolicies -}}
-{{- if ne $netPolItem.Namespace $currentNamespace }}
-Namespace: {{ $netPolItem.Namespace }}
-{{- $currentNamespace = $netPolItem.Namespace -}}
-{{- end }}
+{{- range $group := groupBy "Namespace" .NetworkPolicies }}
+Namespace: {{ $group.Key }}
+{{- range $index, $netPolItem := $group.Items }}
     Name: {{ $netPolItem.Name }}
       Pod Selector: {{ $netPolItem.PodSelector }}
       Policy Types: {{ range $typeIndex, $type := $netPolItem.PolicyTypes }}{{if $typeIndex}}, {{end}}{{ $type }}{{ end }}
@@ -243,7 +271,58 @@ Namespace: {{ $netPolItem.Namespace }}
           - Protocol: {{ .Protocol }} Port: {{ .Port }}
         {{- end }}
       {{- end }}
-      Age: {{ $netPolItem.Age }}
+      Age: {{ humanizeAge $.CreatedAt $netPolItem.CreatedAt }}
+{{- end }}
+{{- end }}
+
+--- Container Images ---
+{{- range $group := groupBy "Image" .ContainerImages }}
+Image: {{ $group.Key }}
+{{- range $index, $imageItem := $group.Items }}
+  - {{ $imageItem.Namespace }}/{{ $imageItem.OwnerKind }}/{{ $imageItem.OwnerName }} {{ if $imageItem.InitContainer }}initContainer{{ else }}container{{ end }}: {{ $imageItem.Container }}{{ if $imageItem.Digest }} ({{ $imageItem.Digest }}){{ end }}
+{{- end }}
+{{- end }}
+
+{{- range $clusterVersion := .ClusterVersions }}
+
+--- Cluster Version ---
+Version:              {{ $clusterVersion.Version }}
+Channel:              {{ $clusterVersion.Channel }}
+Cluster ID:           {{ $clusterVersion.ClusterID }}
+Update History:
+{{- range $update := $clusterVersion.History }}
+  {{ $update.Version }} ({{ $update.State }}){{ with $update.StartedTime }} started {{ . }}{{ end }}
+{{- end }}
+{{- end }}
+
+{{- if .HelmCharts }}
+
+--- Helm Charts ---
+{{- range $chart := .HelmCharts }}
+{{ $chart.Namespace }}/{{ $chart.Name }}: {{ $chart.Chart }}{{ with $chart.Version }} {{ . }}{{ end }}{{ with $chart.TargetNamespace }} (target namespace {{ . }}){{ end }}
+{{- end }}
+{{- end }}
+
+--- Custom Resource Definitions ---
+{{- range $crd := .CustomResourceDefinitions }}
+Name: {{ $crd.Name }}
+  Group:           {{ $crd.Group }}
+  Kind:            {{ $crd.Kind }}
+  Scope:           {{ $crd.Scope }}
+  Versions:        {{ range $i, $version := $crd.Versions }}{{ if $i }}, {{ end }}{{ $version }}{{ end }}
+  Stored Versions: {{ range $i, $version := $crd.StoredVersions }}{{ if $i }}, {{ end }}{{ $version }}{{ end }}
+  Instances:       {{ with $crd.Total }}{{ . }}{{ else }}unknown{{ end }}
+  {{- range $count := $crd.Instances }}{{ with $count.Namespace }}
+    {{ . }}: {{ $count.Count }}{{ end }}{{ end }}
+{{- end }}
+{{- if .CustomResources }}
+
+--- Custom Resources ---
+{{- range $dump := .CustomResources }}
+{{ $dump.Kind }} ({{ $dump.Resource }}{{ with $dump.Group }}.{{ . }}{{ end }}/{{ $dump.Version }}): {{ len $dump.Items }} objects
+{{- range $object := $dump.Items }}{{ with index $object "metadata" }}
+  - {{ with index . "namespace" }}{{ . }}/{{ end }}{{ index . "name" }}{{ end }}{{ end }}
+{{- end }}
 {{- end }}
 {{- end }}
 `
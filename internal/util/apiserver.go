@@ -0,0 +1,129 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+)
+
+// APIServerInfo is the version of the API server and the API groups it serves.
+type APIServerInfo struct {
+	GitVersion  string            `json:"gitVersion"` // e.g. v1.27.3+rke2r1
+	GitCommit   string            `json:"gitCommit"`
+	BuildDate   string            `json:"buildDate"`
+	GoVersion   string            `json:"goVersion"`
+	Platform    string            `json:"platform"` // e.g. linux/amd64
+	Groups      []APIGroupVersion `json:"groups"`
+	KubeletSkew []KubeletSkewItem `json:"kubeletSkew,omitempty"` // kubelets outside the supported version skew
+}
+
+// APIGroupVersion is a group version the API server serves, e.g. apps/v1, with its resources.
+type APIGroupVersion struct {
+	GroupVersion string   `json:"groupVersion"`
+	Preferred    bool     `json:"preferred"` // whether it is the preferred version of its group
+	Resources    []string `json:"resources"` // without subresources
+}
+
+// KubeletSkewItem is a node whose kubelet version is not supported with the API server version.
+type KubeletSkewItem struct {
+	Node           string `json:"node"`
+	KubeletVersion string `json:"kubeletVersion"`
+	Reason         string `json:"reason"`
+}
+
+// GetAPIServerInfo asks the API server for its version and the group versions and resources it serves. When some
+// aggregated APIs are unavailable, their group versions are returned without resources and a warning is recorded.
+func (k *KubeConfig) GetAPIServerInfo(ctx context.Context) (APIServerInfo, error) {
+	var serverVersion k8sversion.Info
+	if err := k.discoveryGet(ctx, "/version", &serverVersion); err != nil {
+		return APIServerInfo{}, &ResourceError{Resource: "version", Err: fmt.Errorf("failed to get the API server version: %w", err)}
+	}
+	info := APIServerInfo{
+		GitVersion: serverVersion.GitVersion,
+		GitCommit:  serverVersion.GitCommit,
+		BuildDate:  serverVersion.BuildDate,
+		GoVersion:  serverVersion.GoVersion,
+		Platform:   serverVersion.Platform,
+	}
+
+	groups, err := k.serverGroups(ctx)
+	if err != nil {
+		return info, &ResourceError{Resource: "discovery", Err: fmt.Errorf("failed to discover API groups: %w", err)}
+	}
+	var groupVersions []string
+	for _, group := range groups {
+		for _, groupVersion := range group.Versions {
+			groupVersions = append(groupVersions, groupVersion.GroupVersion)
+		}
+	}
+	resourceLists, err := k.serverResources(ctx, groupVersions)
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return info, &ResourceError{Resource: "discovery", Err: fmt.Errorf("failed to discover API resources: %w", err)}
+		}
+		// the group versions are still reported, without resources
+		warn(ctx, "discovery", err)
+	}
+
+	resources := map[string][]string{}
+	for _, resourceList := range resourceLists {
+		for _, resource := range resourceList.APIResources {
+			if !strings.Contains(resource.Name, "/") {
+				resources[resourceList.GroupVersion] = append(resources[resourceList.GroupVersion], resource.Name)
+			}
+		}
+	}
+	for _, group := range groups {
+		for _, groupVersion := range group.Versions {
+			sort.Strings(resources[groupVersion.GroupVersion])
+			info.Groups = append(info.Groups, APIGroupVersion{
+				GroupVersion: groupVersion.GroupVersion,
+				Preferred:    groupVersion.GroupVersion == group.PreferredVersion.GroupVersion,
+				Resources:    resources[groupVersion.GroupVersion],
+			})
+		}
+	}
+	sort.Slice(info.Groups, func(i, j int) bool {
+		return info.Groups[i].GroupVersion < info.Groups[j].GroupVersion
+	})
+	return info, nil
+}
+
+// KubeletSkew returns the nodes whose kubelet is newer than the API server or more minor versions older than the
+// version skew policy supports: two before Kubernetes 1.28, three since.
+func KubeletSkew(apiServerVersion string, nodes NodeInfo) []KubeletSkewItem {
+	server, err := version.ParseGeneric(apiServerVersion)
+	if err != nil {
+		return nil
+	}
+	maxOlder := uint(2)
+	if server.Major() == 1 && server.Minor() >= 28 {
+		maxOlder = 3
+	}
+
+	var skew []KubeletSkewItem
+	for _, node := range nodes.Items {
+		kubelet, err := version.ParseGeneric(node.System.KubeletVersion)
+		if err != nil {
+			continue
+		}
+		var reason string
+		switch {
+		case kubelet.Major() != server.Major():
+			reason = "different major version than the API server"
+		case kubelet.Minor() > server.Minor():
+			reason = "newer than the API server"
+		case server.Minor()-kubelet.Minor() > maxOlder:
+			reason = fmt.Sprintf("%d minor versions older than the API server, at most %d are supported", server.Minor()-kubelet.Minor(), maxOlder)
+		default:
+			continue
+		}
+		skew = append(skew, KubeletSkewItem{Node: node.Name, KubeletVersion: node.System.KubeletVersion, Reason: reason})
+	}
+	return skew
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func kubeletNode(version string) NodeInfo {
+	node := NodeItem{Name: "n1"}
+	node.System.KubeletVersion = version
+	return NodeInfo{Items: []NodeItem{node}}
+}
+
+func TestKubeletSkew(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  string
+		kubelet string
+		want    string
+	}{
+		{"same version", "v1.27.3", "v1.27.3", ""},
+		{"two older before 1.28", "v1.27.3", "v1.25.9", ""},
+		{"three older before 1.28", "v1.27.3", "v1.24.15", "3 minor versions older than the API server, at most 2 are supported"},
+		{"three older from 1.28", "v1.28.2", "v1.25.9", ""},
+		{"four older from 1.28", "v1.28.2", "v1.24.15", "4 minor versions older than the API server, at most 3 are supported"},
+		{"three older after 1.28", "v1.30.0+k3s1", "v1.27.3+k3s1", ""},
+		{"newer", "v1.27.3", "v1.28.2", "newer than the API server"},
+		{"different major", "v1.27.3", "v2.0.0", "different major version than the API server"},
+		{"unparsable kubelet", "v1.27.3", "unknown", ""},
+		{"unparsable server", "unknown", "v1.20.0", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want []KubeletSkewItem
+			if tt.want != "" {
+				want = []KubeletSkewItem{{Node: "n1", KubeletVersion: tt.kubelet, Reason: tt.want}}
+			}
+			if got := KubeletSkew(tt.server, kubeletNode(tt.kubelet)); !reflect.DeepEqual(got, want) {
+				t.Errorf("KubeletSkew(%q, %q) = %+v, want %+v", tt.server, tt.kubelet, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package util
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// kubectl compatible client flags
+var kubeconfigFlag = flag.String("kubeconfig", "", "(optional) absolute path to the kubeconfig file")
+var ContextFlag = flag.String("context", "", "(optional) kubeconfig context to assess instead of the current context")
+var clusterFlag = flag.String("cluster", "", "(optional) kubeconfig cluster to use")
+var userFlag = flag.String("user", "", "(optional) kubeconfig user to use")
+var serverFlag = flag.String("server", "", "(optional) address and port of the Kubernetes API server")
+var tokenFlag = flag.String("token", "", "(optional) bearer token for authentication to the API server")
+var certificateAuthorityFlag = flag.String("certificate-authority", "", "(optional) path to a cert file for the certificate authority")
+var insecureSkipTLSVerifyFlag = flag.Bool("insecure-skip-tls-verify", false, "if true, the server's certificate will not be checked for validity")
+var asFlag = flag.String("as", "", "(optional) username to impersonate for the operation")
+var asGroupFlag stringSliceFlag
+var requestTimeoutFlag = flag.String("request-timeout", "0", "time to wait before giving up on a single server request, e.g. 1s, 2m; 0 means no timeout")
+
+// client-side rate limits, so that kasba does not put load on production API servers
+var qpsFlag = flag.Float64("qps", 5, "maximum queries per second sent to the API server")
+var burstFlag = flag.Int("burst", 10, "maximum burst of queries sent to the API server above --qps")
+
+func init() {
+	flag.Var(&asGroupFlag, "as-group", "(optional) group to impersonate for the operation, can be repeated")
+}
+
+// stringSliceFlag is a flag that can be given several times, collecting every value.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// loadingRules returns the kubectl loading rules: --kubeconfig if set, otherwise the KUBECONFIG file list merged
+// in order, otherwise ~/.kube/config.
+func loadingRules() *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = *kubeconfigFlag
+	return rules
+}
+
+// configOverrides applies the kubectl client flags, selecting context if it is set and --context otherwise.
+func configOverrides(context string) (*clientcmd.ConfigOverrides, error) {
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: context,
+		Timeout:        *requestTimeoutFlag,
+	}
+	if overrides.CurrentContext == "" {
+		overrides.CurrentContext = *ContextFlag
+	}
+	overrides.Context.Cluster = *clusterFlag
+	overrides.Context.AuthInfo = *userFlag
+	overrides.ClusterInfo.Server = *serverFlag
+	overrides.ClusterInfo.CertificateAuthority = *certificateAuthorityFlag
+	overrides.ClusterInfo.InsecureSkipTLSVerify = *insecureSkipTLSVerifyFlag
+	overrides.AuthInfo.Token = *tokenFlag
+	overrides.AuthInfo.Impersonate = *asFlag
+	overrides.AuthInfo.ImpersonateGroups = asGroupFlag
+
+	if *insecureSkipTLSVerifyFlag && *certificateAuthorityFlag != "" {
+		return nil, fmt.Errorf("--insecure-skip-tls-verify and --certificate-authority are mutually exclusive")
+	}
+	return overrides, nil
+}
+
+// ListContexts returns the names of all contexts in the merged kubeconfig, sorted by name.
+func ListContexts() ([]string, error) {
+	config, err := loadingRules().Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+
+	var contexts []string
+	for name := range config.Contexts {
+		contexts = append(contexts, name)
+	}
+	if len(contexts) == 0 {
+		return nil, fmt.Errorf("no contexts found in kubeconfig")
+	}
+	sort.Strings(contexts)
+	return contexts, nil
+}
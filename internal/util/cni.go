@@ -0,0 +1,483 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// NetworkPluginUnknown is the name of the network plugin when no known CNI was found.
+const NetworkPluginUnknown = "unknown"
+
+// NetworkPlugin is a CNI plugin running in the cluster.
+type NetworkPlugin struct {
+	Name      string          `json:"name"`                // e.g. Cilium or NetworkPluginUnknown
+	Version   string          `json:"version,omitempty"`   // image tag of the CNI agent
+	Mode      []string        `json:"mode,omitempty"`      // e.g. VXLAN, BGP, eBPF or kube-proxy replacement
+	Evidence  []string        `json:"evidence,omitempty"`  // e.g. "DaemonSet kube-system/cilium"
+	Secondary []NetworkPlugin `json:"secondary,omitempty"` // further CNIs, e.g. Multus or a policy-only Calico
+}
+
+// cniMatch is what was found of a plugin in the cluster, used to work out its version and mode.
+type cniMatch struct {
+	daemonSet  *appsv1.DaemonSet
+	container  *v1.Container                // the agent container of daemonSet
+	configMaps map[string]map[string]string // data of the plugin's ConfigMaps that exist, by name
+	nodes      NodeInfo
+}
+
+// env returns the value of an environment variable of the agent container, or "".
+func (m cniMatch) env(name string) string {
+	if m.container != nil {
+		for _, env := range m.container.Env {
+			if env.Name == name {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+// arg returns the value of a --name=value argument of the agent container, or def if it is not given.
+func (m cniMatch) arg(name, def string) string {
+	if m.container != nil {
+		for _, arg := range append(m.container.Command, m.container.Args...) {
+			if strings.HasPrefix(arg, name+"=") {
+				return strings.TrimPrefix(arg, name+"=")
+			}
+		}
+	}
+	return def
+}
+
+// configMap returns a key of the first of the plugin's ConfigMaps that has it, or "".
+func (m cniMatch) configMap(key string) string {
+	var names []string
+	for name := range m.configMaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if value, ok := m.configMaps[name][key]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// cniPlugins are the plugins kasba recognizes. DaemonSets are matched against them in order, so Canal, which runs
+// the Calico and Flannel images, comes before both. The first plugin found that is not a meta plugin is reported as
+// the primary CNI, so AWS VPC CNI, which Calico or Cilium are often added to for network policies, comes first.
+var cniPlugins = []struct {
+	name       string
+	meta       bool     // meta plugins such as Multus delegate to other CNIs and are always secondary
+	daemonSets []string // names of the agent DaemonSet
+	images     []string // substrings of the agent image
+	configMaps []string // ConfigMaps the plugin is configured with, looked up in the DaemonSet's namespace and kube-system
+	crdGroups  []string // API groups of the plugin's CRDs
+	mode       func(m cniMatch) []string
+}{
+	{name: "AWS VPC CNI", daemonSets: []string{"aws-node"}, images: []string{"amazon-k8s-cni"}, configMaps: []string{"amazon-vpc-cni"}, crdGroups: []string{"crd.k8s.amazonaws.com", "vpcresources.k8s.aws"}, mode: awsVPCMode},
+	{name: "Canal", daemonSets: []string{"canal", "rke2-canal"}, configMaps: []string{"canal-config", "rke2-canal-config"}, mode: canalMode},
+	{name: "Cilium", daemonSets: []string{"cilium", "anetd"}, images: []string{"cilium/cilium", "mirrored-cilium-cilium"}, configMaps: []string{"cilium-config"}, crdGroups: []string{"cilium.io"}, mode: ciliumMode},
+	{name: "Calico", daemonSets: []string{"calico-node"}, images: []string{"calico/node", "hardened-calico"}, configMaps: []string{"calico-config"}, crdGroups: []string{"crd.projectcalico.org", "operator.tigera.io"}, mode: calicoMode},
+	{name: "Flannel", daemonSets: []string{"kube-flannel-ds", "kube-flannel", "flannel"}, images: []string{"flannel/flannel", "flannelcni/flannel", "hardened-flannel"}, configMaps: []string{"kube-flannel-cfg"}, mode: flannelMode},
+	{name: "Weave Net", daemonSets: []string{"weave-net"}, images: []string{"weaveworks/weave-kube"}},
+	{name: "kube-router", daemonSets: []string{"kube-router"}, images: []string{"kube-router"}, configMaps: []string{"kube-router-cfg"}, mode: kubeRouterMode},
+	{name: "Antrea", daemonSets: []string{"antrea-agent"}, images: []string{"antrea/antrea-agent", "antrea/antrea-ubuntu"}, configMaps: []string{"antrea-config"}, crdGroups: []string{"crd.antrea.io"}, mode: antreaMode},
+	{name: "OVN-Kubernetes", daemonSets: []string{"ovnkube-node"}, images: []string{"ovn-kubernetes", "ovn-daemonset"}, crdGroups: []string{"k8s.ovn.org"}, mode: func(cniMatch) []string { return []string{"Geneve"} }},
+	{name: "Multus", meta: true, daemonSets: []string{"kube-multus-ds", "multus"}, images: []string{"multus-cni"}, configMaps: []string{"multus-cni-config"}, crdGroups: []string{"k8s.cni.cncf.io"}},
+}
+
+// flannelBackendAnnotation is set on every node by Flannel, including the one embedded in K3s that runs without a
+// DaemonSet.
+const flannelBackendAnnotation = "flannel.alpha.coreos.com/backend-type"
+
+// GetNetworkPlugin detects the CNI plugins from their agent DaemonSets, their ConfigMaps and CRDs, and the
+// annotations Flannel sets on nodes. The version is the image tag of the agent, the mode is read from the agent's
+// environment and arguments and from the ConfigMaps. DaemonSets, ConfigMaps and CRDs that may not be listed only
+// take evidence away from the detection; without the DaemonSets, plugins are only found by their ConfigMaps in
+// cniNamespaces and by the node annotations, and their versions are unknown.
+func (k *KubeConfig) GetNetworkPlugin(ctx context.Context, nodes NodeInfo) (NetworkPlugin, error) {
+	var daemonSets []appsv1.DaemonSet
+	list, err := listAll(ctx, k, "daemonsets", k.clientset.AppsV1().DaemonSets(metav1.NamespaceAll).List)
+	switch {
+	case err == nil:
+		daemonSets = list.Items
+	case !apierrors.IsForbidden(err):
+		return NetworkPlugin{Name: NetworkPluginUnknown}, err
+	}
+	configMaps := k.configMapData(ctx, cniNamespaces(daemonSets))
+	return detectNetworkPlugin(daemonSets, configMaps, k.crdGroups(ctx), nodes), nil
+}
+
+// defaultCNINamespaces are the namespaces plugins install their ConfigMaps in by default, besides kube-system.
+var defaultCNINamespaces = []string{"kube-flannel", "calico-system"}
+
+// cniNamespaces returns the namespaces the plugins' ConfigMaps are looked up in: kube-system, defaultCNINamespaces
+// and those of the plugins' DaemonSets.
+func cniNamespaces(daemonSets []appsv1.DaemonSet) []string {
+	namespaces := append([]string{"kube-system"}, defaultCNINamespaces...)
+	for d := range daemonSets {
+		ds := &daemonSets[d]
+		if cniPluginFor(ds) >= 0 && !contains(namespaces, ds.Namespace) {
+			namespaces = append(namespaces, ds.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// detectNetworkPlugin works out the CNI plugins from the DaemonSets of the cluster, the data of the ConfigMaps in
+// cniNamespaces by namespace/name, the API groups that have CRDs and the nodes.
+func detectNetworkPlugin(daemonSets []appsv1.DaemonSet, configMaps map[string]map[string]string, crdGroups map[string]bool, nodes NodeInfo) NetworkPlugin {
+	daemonSets = append([]appsv1.DaemonSet(nil), daemonSets...)
+	sort.Slice(daemonSets, func(i, j int) bool {
+		a, b := daemonSets[i], daemonSets[j]
+		return lessNamespacedName(a.Namespace, a.Name, b.Namespace, b.Name)
+	})
+	namespaces := cniNamespaces(daemonSets)
+
+	var found []NetworkPlugin
+	for i, plugin := range cniPlugins {
+		var match cniMatch
+		var evidence []string
+		for d := range daemonSets {
+			ds := &daemonSets[d]
+			if cniPluginFor(ds) == i {
+				if match.daemonSet == nil {
+					match.daemonSet, match.container = ds, agentContainer(ds, plugin.images)
+				}
+				evidence = append(evidence, "DaemonSet "+ds.Namespace+"/"+ds.Name)
+			}
+		}
+
+		match.configMaps = map[string]map[string]string{}
+		for _, name := range plugin.configMaps {
+			for _, namespace := range namespaces {
+				if data, ok := configMaps[namespace+"/"+name]; ok {
+					match.configMaps[name] = data
+					evidence = append(evidence, "ConfigMap "+namespace+"/"+name)
+					break
+				}
+			}
+		}
+
+		// CRDs outlive uninstalled plugins and Canal installs Calico's, so they only support other evidence
+		if len(evidence) == 0 {
+			continue
+		}
+		for _, group := range plugin.crdGroups {
+			if crdGroups[group] {
+				evidence = append(evidence, "CRDs in "+group)
+			}
+		}
+
+		match.nodes = nodes
+		item := NetworkPlugin{Name: plugin.name, Evidence: evidence}
+		if match.container != nil {
+			item.Version = imageTag(match.container.Image)
+		}
+		if plugin.mode != nil {
+			item.Mode = plugin.mode(match)
+		}
+		found = append(found, item)
+	}
+
+	// K3s embeds Flannel, which leaves no trace but the node annotations
+	if len(found) == 0 {
+		if backend := nodeAnnotationValue(nodes, flannelBackendAnnotation); backend != "" {
+			found = append(found, NetworkPlugin{
+				Name:     "Flannel",
+				Mode:     []string{strings.ToUpper(backend)},
+				Evidence: []string{"node annotation " + flannelBackendAnnotation},
+			})
+		}
+	}
+	return primaryNetworkPlugin(found)
+}
+
+// primaryNetworkPlugin returns the first of the found plugins that is not a meta plugin, with all others as its
+// secondary plugins, or NetworkPluginUnknown with all of them as secondary.
+func primaryNetworkPlugin(found []NetworkPlugin) NetworkPlugin {
+	primary := NetworkPlugin{Name: NetworkPluginUnknown}
+	var secondary []NetworkPlugin
+	for _, item := range found {
+		if primary.Name == NetworkPluginUnknown && !isMetaCNI(item.Name) {
+			primary = item
+			continue
+		}
+		secondary = append(secondary, item)
+	}
+	primary.Secondary = secondary
+	return primary
+}
+
+// cniPluginFor returns the index in cniPlugins of the plugin the DaemonSet runs, or -1.
+func cniPluginFor(ds *appsv1.DaemonSet) int {
+	for i, plugin := range cniPlugins {
+		for _, name := range plugin.daemonSets {
+			if ds.Name == name {
+				return i
+			}
+		}
+	}
+	for i, plugin := range cniPlugins {
+		if imageContainer(ds, plugin.images) != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// agentContainer returns the container running one of images, falling back to the first container.
+func agentContainer(ds *appsv1.DaemonSet, images []string) *v1.Container {
+	if container := imageContainer(ds, images); container != nil {
+		return container
+	}
+	if len(ds.Spec.Template.Spec.Containers) > 0 {
+		return &ds.Spec.Template.Spec.Containers[0]
+	}
+	return nil
+}
+
+// imageContainer returns the first container whose image contains one of images, or nil.
+func imageContainer(ds *appsv1.DaemonSet, images []string) *v1.Container {
+	containers := ds.Spec.Template.Spec.Containers
+	for c := range containers {
+		for _, image := range images {
+			if strings.Contains(containers[c].Image, image) {
+				return &containers[c]
+			}
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isMetaCNI(name string) bool {
+	for _, plugin := range cniPlugins {
+		if plugin.name == name {
+			return plugin.meta
+		}
+	}
+	return false
+}
+
+// imageTag returns the tag of an image reference, e.g. v1.14.2 for quay.io/cilium/cilium:v1.14.2@sha256:..., or ""
+// if it has none.
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	slash := strings.LastIndex(image, "/")
+	if colon := strings.LastIndex(image, ":"); colon > slash {
+		return image[colon+1:]
+	}
+	return ""
+}
+
+// configMapData returns the data of the ConfigMaps in namespaces by namespace/name. Namespaces whose ConfigMaps
+// cannot be listed are recorded as a warning and left out. Missing permissions are not, the collector reports them.
+func (k *KubeConfig) configMapData(ctx context.Context, namespaces []string) map[string]map[string]string {
+	data := map[string]map[string]string{}
+	for _, namespace := range namespaces {
+		list, err := listAll(ctx, k, "configmaps", k.clientset.CoreV1().ConfigMaps(namespace).List)
+		if err != nil {
+			if !apierrors.IsForbidden(err) {
+				warn(ctx, "configmaps", fmt.Errorf("CNI detection: failed to list ConfigMaps in %s: %w", namespace, err))
+			}
+			continue
+		}
+		for _, configMap := range list.Items {
+			data[configMap.Namespace+"/"+configMap.Name] = configMap.Data
+		}
+	}
+	return data
+}
+
+// crdGroups returns the API groups that have CRDs installed. Only metadata is listed. Errors, e.g. for lack of
+// permissions, leave the result empty.
+func (k *KubeConfig) crdGroups(ctx context.Context) map[string]bool {
+	groups := map[string]bool{}
+	list, err := listAll(ctx, k, crdResource.Resource, k.metadata.Resource(crdResource).List)
+	if err != nil {
+		return groups
+	}
+	for _, crd := range list.Items {
+		// CRDs are named <plural>.<group>
+		if _, group, ok := strings.Cut(crd.Name, "."); ok {
+			groups[group] = true
+		}
+	}
+	return groups
+}
+
+func nodeAnnotationValue(nodes NodeInfo, key string) string {
+	for _, node := range nodes.Items {
+		if value := node.Annotations[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func ciliumMode(m cniMatch) []string {
+	mode := []string{"eBPF"}
+	switch routing := m.configMap("routing-mode"); {
+	case routing == "native":
+		mode = append(mode, "native routing")
+	case routing == "tunnel":
+		protocol := m.configMap("tunnel-protocol")
+		if protocol == "" {
+			protocol = "vxlan"
+		}
+		mode = append(mode, strings.ToUpper(protocol))
+	default:
+		// before Cilium 1.14 the tunnel key held both
+		switch tunnel := m.configMap("tunnel"); tunnel {
+		case "":
+		case "disabled":
+			mode = append(mode, "native routing")
+		default:
+			mode = append(mode, strings.ToUpper(tunnel))
+		}
+	}
+	switch m.configMap("kube-proxy-replacement") {
+	case "true", "strict":
+		mode = append(mode, "kube-proxy replacement")
+	case "partial":
+		mode = append(mode, "partial kube-proxy replacement")
+	}
+	return mode
+}
+
+func calicoMode(m cniMatch) []string {
+	var mode []string
+	backend := m.env("CALICO_NETWORKING_BACKEND")
+	if backend == "" {
+		backend = m.configMap("calico_backend")
+	}
+	switch backend {
+	case "bird":
+		mode = append(mode, "BGP")
+	case "vxlan":
+		mode = append(mode, "VXLAN")
+	case "none":
+		mode = append(mode, "policy only")
+	}
+	if ipip := m.env("CALICO_IPV4POOL_IPIP"); ipip != "" && ipip != "Never" {
+		mode = append(mode, "IPIP "+ipip)
+	}
+	if vxlan := m.env("CALICO_IPV4POOL_VXLAN"); vxlan != "" && vxlan != "Never" {
+		mode = append(mode, "VXLAN "+vxlan)
+	}
+	if strings.EqualFold(m.env("FELIX_BPFENABLED"), "true") {
+		mode = append(mode, "eBPF")
+	}
+	return mode
+}
+
+func flannelMode(m cniMatch) []string {
+	if backend := flannelBackend(m.configMap("net-conf.json")); backend != "" {
+		return []string{backend}
+	}
+	if backend := nodeAnnotationValue(m.nodes, flannelBackendAnnotation); backend != "" {
+		return []string{strings.ToUpper(backend)}
+	}
+	return nil
+}
+
+func canalMode(m cniMatch) []string {
+	mode := []string{"Calico policy"}
+	for _, backend := range flannelMode(m) {
+		mode = append(mode, "Flannel "+backend)
+	}
+	return mode
+}
+
+// flannelBackend returns the backend type in Flannel's net-conf.json, e.g. VXLAN, or "".
+func flannelBackend(netConf string) string {
+	var conf struct {
+		Backend struct {
+			Type string
+		}
+	}
+	if err := json.Unmarshal([]byte(netConf), &conf); err != nil {
+		return ""
+	}
+	return strings.ToUpper(conf.Backend.Type)
+}
+
+func kubeRouterMode(m cniMatch) []string {
+	var mode []string
+	if m.arg("--run-router", "true") == "true" {
+		mode = append(mode, "BGP")
+	}
+	if m.arg("--run-service-proxy", "true") == "true" {
+		mode = append(mode, "kube-proxy replacement")
+	}
+	if m.arg("--run-firewall", "true") == "true" {
+		mode = append(mode, "network policy")
+	}
+	return mode
+}
+
+func antreaMode(m cniMatch) []string {
+	var conf struct {
+		TrafficEncapMode string `json:"trafficEncapMode"`
+		TunnelType       string `json:"tunnelType"`
+		AntreaProxy      struct {
+			ProxyAll bool `json:"proxyAll"`
+		} `json:"antreaProxy"`
+	}
+	if err := yaml.Unmarshal([]byte(m.configMap("antrea-agent.conf")), &conf); err != nil {
+		return nil
+	}
+
+	var mode []string
+	switch conf.TrafficEncapMode {
+	case "", "encap":
+		tunnel := conf.TunnelType
+		if tunnel == "" {
+			tunnel = "geneve"
+		}
+		mode = append(mode, strings.ToUpper(tunnel))
+	case "noEncap":
+		mode = append(mode, "native routing")
+	default:
+		mode = append(mode, conf.TrafficEncapMode)
+	}
+	if conf.AntreaProxy.ProxyAll {
+		mode = append(mode, "kube-proxy replacement")
+	}
+	return mode
+}
+
+func awsVPCMode(m cniMatch) []string {
+	mode := []string{"VPC native routing"}
+	for env, name := range map[string]string{
+		"AWS_VPC_K8S_CNI_CUSTOM_NETWORK_CFG": "custom networking",
+		"ENABLE_PREFIX_DELEGATION":           "prefix delegation",
+		"ENABLE_POD_ENI":                     "security groups for pods",
+	} {
+		if strings.EqualFold(m.env(env), "true") {
+			mode = append(mode, name)
+		}
+	}
+	sort.Strings(mode[1:])
+	return mode
+}
@@ -0,0 +1,236 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func daemonSet(namespace, name string, containers ...v1.Container) appsv1.DaemonSet {
+	ds := appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	ds.Spec.Template.Spec.Containers = containers
+	return ds
+}
+
+func flannelNodes(backend string) NodeInfo {
+	return NodeInfo{Items: []NodeItem{{Name: "n1", Annotations: map[string]string{flannelBackendAnnotation: backend}}}}
+}
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"quay.io/cilium/cilium:v1.14.2", "v1.14.2"},
+		{"quay.io/cilium/cilium:v1.14.2@sha256:abc", "v1.14.2"},
+		{"quay.io/cilium/cilium@sha256:abc", ""},
+		{"registry.local:5000/calico/node", ""},
+		{"registry.local:5000/calico/node:v3.26.1", "v3.26.1"},
+		{"flannel", ""},
+	}
+	for _, tt := range tests {
+		if got := imageTag(tt.image); got != tt.want {
+			t.Errorf("imageTag(%q) = %q, want %q", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestCNIPluginFor(t *testing.T) {
+	tests := []struct {
+		name string
+		ds   appsv1.DaemonSet
+		want string
+	}{
+		{"by name", daemonSet("kube-system", "rke2-canal", v1.Container{Image: "rancher/hardened-calico:v3.26.1"}), "Canal"},
+		{"by name before image", daemonSet("kube-system", "canal", v1.Container{Image: "calico/node:v3.26.1"}, v1.Container{Image: "flannel/flannel:v0.22.0"}), "Canal"},
+		{"by image", daemonSet("networking", "agent", v1.Container{Image: "quay.io/cilium/cilium:v1.14.2"}), "Cilium"},
+		{"GKE Dataplane V2", daemonSet("kube-system", "anetd"), "Cilium"},
+		{"meta plugin", daemonSet("kube-system", "kube-multus-ds", v1.Container{Image: "ghcr.io/k8snetworkplumbingwg/multus-cni:v4.0.2"}), "Multus"},
+		{"unrelated", daemonSet("kube-system", "kube-proxy", v1.Container{Image: "registry.k8s.io/kube-proxy:v1.27.3"}), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if i := cniPluginFor(&tt.ds); i >= 0 {
+				got = cniPlugins[i].name
+			}
+			if got != tt.want {
+				t.Errorf("cniPluginFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModes(t *testing.T) {
+	container := func(env map[string]string, args ...string) *v1.Container {
+		c := &v1.Container{Args: args}
+		for name, value := range env {
+			c.Env = append(c.Env, v1.EnvVar{Name: name, Value: value})
+		}
+		return c
+	}
+	configMap := func(name string, data map[string]string) map[string]map[string]string {
+		return map[string]map[string]string{name: data}
+	}
+
+	tests := []struct {
+		name  string
+		mode  func(cniMatch) []string
+		match cniMatch
+		want  []string
+	}{
+		{"cilium tunnel", ciliumMode, cniMatch{configMaps: configMap("cilium-config", map[string]string{"routing-mode": "tunnel", "tunnel-protocol": "geneve"})}, []string{"eBPF", "GENEVE"}},
+		{"cilium tunnel default protocol", ciliumMode, cniMatch{configMaps: configMap("cilium-config", map[string]string{"routing-mode": "tunnel"})}, []string{"eBPF", "VXLAN"}},
+		{"cilium native with kube-proxy replacement", ciliumMode, cniMatch{configMaps: configMap("cilium-config", map[string]string{"routing-mode": "native", "kube-proxy-replacement": "true"})}, []string{"eBPF", "native routing", "kube-proxy replacement"}},
+		{"cilium before 1.14", ciliumMode, cniMatch{configMaps: configMap("cilium-config", map[string]string{"tunnel": "disabled", "kube-proxy-replacement": "partial"})}, []string{"eBPF", "native routing", "partial kube-proxy replacement"}},
+		{"cilium without config", ciliumMode, cniMatch{}, []string{"eBPF"}},
+		{"calico BGP with IPIP", calicoMode, cniMatch{container: container(map[string]string{"CALICO_NETWORKING_BACKEND": "bird", "CALICO_IPV4POOL_IPIP": "Always"})}, []string{"BGP", "IPIP Always"}},
+		{"calico backend from ConfigMap", calicoMode, cniMatch{configMaps: configMap("calico-config", map[string]string{"calico_backend": "vxlan"}), container: container(map[string]string{"CALICO_IPV4POOL_IPIP": "Never"})}, []string{"VXLAN"}},
+		{"calico policy only with eBPF", calicoMode, cniMatch{container: container(map[string]string{"CALICO_NETWORKING_BACKEND": "none", "FELIX_BPFENABLED": "True"})}, []string{"policy only", "eBPF"}},
+		{"flannel net-conf", flannelMode, cniMatch{configMaps: configMap("kube-flannel-cfg", map[string]string{"net-conf.json": `{"Network": "10.42.0.0/16", "Backend": {"Type": "vxlan"}}`})}, []string{"VXLAN"}},
+		{"flannel node annotation", flannelMode, cniMatch{nodes: flannelNodes("host-gw")}, []string{"HOST-GW"}},
+		{"flannel unknown", flannelMode, cniMatch{configMaps: configMap("kube-flannel-cfg", map[string]string{"net-conf.json": "not json"})}, nil},
+		{"canal", canalMode, cniMatch{configMaps: configMap("rke2-canal-config", map[string]string{"net-conf.json": `{"Backend": {"Type": "vxlan"}}`})}, []string{"Calico policy", "Flannel VXLAN"}},
+		{"kube-router defaults", kubeRouterMode, cniMatch{container: container(nil)}, []string{"BGP", "kube-proxy replacement", "network policy"}},
+		{"kube-router without proxy", kubeRouterMode, cniMatch{container: container(nil, "--run-service-proxy=false", "--run-firewall=false")}, []string{"BGP"}},
+		{"antrea default", antreaMode, cniMatch{configMaps: configMap("antrea-config", map[string]string{"antrea-agent.conf": "featureGates: {}\n"})}, []string{"GENEVE"}},
+		{"antrea noEncap with proxyAll", antreaMode, cniMatch{configMaps: configMap("antrea-config", map[string]string{"antrea-agent.conf": "trafficEncapMode: noEncap\nantreaProxy:\n  proxyAll: true\n"})}, []string{"native routing", "kube-proxy replacement"}},
+		{"antrea invalid config", antreaMode, cniMatch{configMaps: configMap("antrea-config", map[string]string{"antrea-agent.conf": "trafficEncapMode: [\n"})}, nil},
+		{"aws vpc", awsVPCMode, cniMatch{container: container(map[string]string{"ENABLE_PREFIX_DELEGATION": "true", "ENABLE_POD_ENI": "true", "AWS_VPC_K8S_CNI_CUSTOM_NETWORK_CFG": "false"})}, []string{"VPC native routing", "prefix delegation", "security groups for pods"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mode(tt.match); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCNINamespaces(t *testing.T) {
+	tests := []struct {
+		name       string
+		daemonSets []appsv1.DaemonSet
+		want       []string
+	}{
+		{"without DaemonSets", nil, []string{"kube-system", "kube-flannel", "calico-system"}},
+		{"plugin namespace", []appsv1.DaemonSet{daemonSet("cilium", "cilium"), daemonSet("monitoring", "node-exporter")}, []string{"kube-system", "kube-flannel", "calico-system", "cilium"}},
+		{"default namespace", []appsv1.DaemonSet{daemonSet("kube-flannel", "kube-flannel-ds")}, []string{"kube-system", "kube-flannel", "calico-system"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cniNamespaces(tt.daemonSets); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cniNamespaces() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrimaryNetworkPlugin(t *testing.T) {
+	tests := []struct {
+		name          string
+		found         []string
+		wantPrimary   string
+		wantSecondary []string
+	}{
+		{"none", nil, NetworkPluginUnknown, nil},
+		{"single", []string{"Cilium"}, "Cilium", nil},
+		{"meta plugin first", []string{"Multus", "Calico"}, "Calico", []string{"Multus"}},
+		{"policy plugin on AWS VPC CNI", []string{"AWS VPC CNI", "Calico"}, "AWS VPC CNI", []string{"Calico"}},
+		{"meta plugin only", []string{"Multus"}, NetworkPluginUnknown, []string{"Multus"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var found []NetworkPlugin
+			for _, name := range tt.found {
+				found = append(found, NetworkPlugin{Name: name})
+			}
+			got := primaryNetworkPlugin(found)
+			var secondary []string
+			for _, plugin := range got.Secondary {
+				secondary = append(secondary, plugin.Name)
+			}
+			if got.Name != tt.wantPrimary || !reflect.DeepEqual(secondary, tt.wantSecondary) {
+				t.Errorf("primaryNetworkPlugin() = %s with %q, want %s with %q", got.Name, secondary, tt.wantPrimary, tt.wantSecondary)
+			}
+		})
+	}
+}
+
+func TestDetectNetworkPlugin(t *testing.T) {
+	tests := []struct {
+		name       string
+		daemonSets []appsv1.DaemonSet
+		configMaps map[string]map[string]string
+		crdGroups  map[string]bool
+		nodes      NodeInfo
+		want       NetworkPlugin
+	}{
+		{
+			name:       "RKE2 Canal",
+			daemonSets: []appsv1.DaemonSet{daemonSet("kube-system", "rke2-canal", v1.Container{Name: "calico-node", Image: "rancher/hardened-calico:v3.26.1-build20230802"}, v1.Container{Name: "kube-flannel", Image: "rancher/hardened-flannel:v0.22.1-build20230802"})},
+			configMaps: map[string]map[string]string{"kube-system/rke2-canal-config": {"net-conf.json": `{"Backend": {"Type": "vxlan"}}`}},
+			crdGroups:  map[string]bool{"crd.projectcalico.org": true},
+			want: NetworkPlugin{
+				Name:     "Canal",
+				Version:  "v3.26.1-build20230802",
+				Mode:     []string{"Calico policy", "Flannel VXLAN"},
+				Evidence: []string{"DaemonSet kube-system/rke2-canal", "ConfigMap kube-system/rke2-canal-config"},
+			},
+		},
+		{
+			name: "Cilium with Multus",
+			daemonSets: []appsv1.DaemonSet{
+				daemonSet("kube-system", "kube-multus-ds", v1.Container{Image: "ghcr.io/k8snetworkplumbingwg/multus-cni:v4.0.2"}),
+				daemonSet("cilium", "cilium", v1.Container{Image: "quay.io/cilium/cilium:v1.14.2@sha256:abc"}),
+			},
+			configMaps: map[string]map[string]string{"cilium/cilium-config": {"routing-mode": "native"}},
+			crdGroups:  map[string]bool{"cilium.io": true, "k8s.cni.cncf.io": true},
+			want: NetworkPlugin{
+				Name:     "Cilium",
+				Version:  "v1.14.2",
+				Mode:     []string{"eBPF", "native routing"},
+				Evidence: []string{"DaemonSet cilium/cilium", "ConfigMap cilium/cilium-config", "CRDs in cilium.io"},
+				Secondary: []NetworkPlugin{{
+					Name:     "Multus",
+					Version:  "v4.0.2",
+					Evidence: []string{"DaemonSet kube-system/kube-multus-ds", "CRDs in k8s.cni.cncf.io"},
+				}},
+			},
+		},
+		{
+			name:  "K3s embedded Flannel",
+			nodes: flannelNodes("vxlan"),
+			want: NetworkPlugin{
+				Name:     "Flannel",
+				Mode:     []string{"VXLAN"},
+				Evidence: []string{"node annotation " + flannelBackendAnnotation},
+			},
+		},
+		{
+			name:       "Flannel ConfigMap without DaemonSets",
+			configMaps: map[string]map[string]string{"kube-flannel/kube-flannel-cfg": {"net-conf.json": `{"Backend": {"Type": "host-gw"}}`}},
+			want: NetworkPlugin{
+				Name:     "Flannel",
+				Mode:     []string{"HOST-GW"},
+				Evidence: []string{"ConfigMap kube-flannel/kube-flannel-cfg"},
+			},
+		},
+		{
+			name:      "CRDs of an uninstalled plugin",
+			crdGroups: map[string]bool{"crd.projectcalico.org": true},
+			want:      NetworkPlugin{Name: NetworkPluginUnknown},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectNetworkPlugin(tt.daemonSets, tt.configMaps, tt.crdGroups, tt.nodes)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("detectNetworkPlugin() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
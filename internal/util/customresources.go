@@ -0,0 +1,284 @@
+package util
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// CustomResourceFlag lists the kinds, as group/kind, whose objects are included in the report in full.
+var CustomResourceFlag stringSliceFlag
+
+func init() {
+	flag.Var(&CustomResourceFlag, "custom-resource", "(optional) group/kind of objects to include in full, e.g. cert-manager.io/Certificate or /ConfigMap for the core group; can be repeated")
+}
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+var helmChartResource = schema.GroupVersionResource{Group: "helm.cattle.io", Version: "v1", Resource: "helmcharts"}
+
+var clusterVersionResource = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusterversions"}
+
+// GetCustomResourceDefinitions lists every installed CRD and counts the instances of its kind per namespace,
+// --parallelism kinds at a time. A kind whose instances cannot be listed is recorded as a warning and reported
+// without counts; kinds the current user may not list, which is all of them with the role of "kasba rbac", share a
+// single warning.
+func (k *KubeConfig) GetCustomResourceDefinitions(ctx context.Context) ([]CustomResourceDefinitionItem, error) {
+	crds, err := listAll(ctx, k, crdResource.Resource, k.dynamic.Resource(crdResource).List)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom resource definitions: %w", err)
+	}
+
+	items := make([]CustomResourceDefinitionItem, len(crds.Items))
+	countVersions := make([]string, len(crds.Items))
+	for i, crd := range crds.Items {
+		item := CustomResourceDefinitionItem{Name: crd.GetName()}
+		item.Group, _, _ = unstructured.NestedString(crd.Object, "spec", "group")
+		item.Kind, _, _ = unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		item.Plural, _, _ = unstructured.NestedString(crd.Object, "spec", "names", "plural")
+		item.Scope, _, _ = unstructured.NestedString(crd.Object, "spec", "scope")
+		item.StoredVersions, _, _ = unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+
+		// count in the storage version if it is served, otherwise in the first served version
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		for _, v := range versions {
+			version, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(version, "name")
+			served, _, _ := unstructured.NestedBool(version, "served")
+			storage, _, _ := unstructured.NestedBool(version, "storage")
+			if !served {
+				continue
+			}
+			item.Versions = append(item.Versions, name)
+			if storage || countVersions[i] == "" {
+				countVersions[i] = name
+			}
+		}
+		items[i] = item
+	}
+
+	// count the kinds --parallelism at a time
+	errs := make([]error, len(items))
+	parallel(len(items), func(i int) {
+		if countVersions[i] == "" {
+			return
+		}
+		gvr := schema.GroupVersionResource{Group: items[i].Group, Version: countVersions[i], Resource: items[i].Plural}
+		items[i].Instances, items[i].Total, errs[i] = k.countCustomResources(ctx, gvr)
+	})
+	if ctx.Err() != nil {
+		return items, ctx.Err()
+	}
+
+	var forbidden []string
+	for i, err := range errs {
+		switch {
+		case err == nil:
+		case apierrors.IsForbidden(err):
+			forbidden = append(forbidden, items[i].Name)
+		default:
+			warn(ctx, items[i].Name, fmt.Errorf("failed to count instances: %w", err))
+		}
+	}
+
+	if len(forbidden) > 0 {
+		sort.Strings(forbidden)
+		warn(ctx, "customresourcedefinitions", apierrors.NewForbidden(schema.GroupResource{}, "",
+			fmt.Errorf("not allowed to list the instances of %d custom resource kinds, reported without counts: %s", len(forbidden), strings.Join(forbidden, ", "))))
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+// countCustomResources counts the objects of a resource per namespace. Only their metadata is listed.
+func (k *KubeConfig) countCustomResources(ctx context.Context, gvr schema.GroupVersionResource) ([]CustomResourceCount, *int, error) {
+	resource := gvr.GroupResource().String()
+	list, err := listAll(ctx, k, resource, k.metadata.Resource(gvr).List)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counts := map[string]int{}
+	for _, object := range list.Items {
+		counts[object.Namespace]++
+	}
+	var instances []CustomResourceCount
+	for namespace, count := range counts {
+		instances = append(instances, CustomResourceCount{Namespace: namespace, Count: count})
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Namespace < instances[j].Namespace
+	})
+	total := len(list.Items)
+	return instances, &total, nil
+}
+
+// GetCustomResources returns every object of the kinds in groupKinds, each given as group/kind. The kinds are
+// looked up with API discovery, so built-in kinds work as well, in their preferred version. Since the resources are
+// only known after discovery, the permission to list each is checked here rather than in the preflight. Kinds that
+// cannot be found or may not be listed are left out and reported in the returned error.
+func (k *KubeConfig) GetCustomResources(ctx context.Context, groupKinds []string) ([]CustomResourceDump, error) {
+	if len(groupKinds) == 0 {
+		return nil, nil
+	}
+
+	groups, err := k.serverGroups(ctx)
+	if err != nil {
+		return nil, &ResourceError{Resource: "discovery", Err: fmt.Errorf("failed to discover API groups: %w", err)}
+	}
+	var preferred []string
+	for _, group := range groups {
+		preferred = append(preferred, group.PreferredVersion.GroupVersion)
+	}
+	resourceLists, err := k.serverResources(ctx, preferred)
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) || len(resourceLists) == 0 {
+			return nil, &ResourceError{Resource: "discovery", Err: fmt.Errorf("failed to discover API resources: %w", err)}
+		}
+		// some aggregated APIs are unavailable, the kinds they serve will not be found
+		warn(ctx, "discovery", err)
+	}
+
+	var dumps []CustomResourceDump
+	var failed []error
+	for _, groupKind := range groupKinds {
+		dump, err := k.dumpCustomResource(ctx, resourceLists, groupKind)
+		if err != nil {
+			if ctx.Err() != nil {
+				return dumps, err
+			}
+			failed = append(failed, err)
+			continue
+		}
+		dumps = append(dumps, dump)
+	}
+	switch len(failed) {
+	case 0:
+		return dumps, nil
+	case 1:
+		return dumps, failed[0]
+	}
+	var messages []string
+	for _, err := range failed {
+		messages = append(messages, err.Error())
+	}
+	return dumps, fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+func (k *KubeConfig) dumpCustomResource(ctx context.Context, resourceLists []*metav1.APIResourceList, groupKind string) (CustomResourceDump, error) {
+	group, kind, ok := strings.Cut(groupKind, "/")
+	if !ok || kind == "" {
+		return CustomResourceDump{}, fmt.Errorf("invalid --custom-resource %q, expected group/kind", groupKind)
+	}
+
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil || gv.Group != group {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			// skip subresources such as certificates/status, which share the kind
+			if strings.Contains(resource.Name, "/") || !strings.EqualFold(resource.Kind, kind) {
+				continue
+			}
+
+			gvr := gv.WithResource(resource.Name)
+			permission, err := k.checkPermission(ctx, Resource{Group: group, Resource: resource.Name})
+			if err != nil {
+				return CustomResourceDump{}, err
+			}
+			if !permission.Allowed {
+				return CustomResourceDump{}, &ResourceError{
+					Resource: gvr.GroupResource().String(),
+					Err:      apierrors.NewForbidden(gvr.GroupResource(), "", fmt.Errorf("insufficient permissions to list %s", groupKind)),
+				}
+			}
+			list, err := listAll(ctx, k, gvr.GroupResource().String(), k.dynamic.Resource(gvr).List)
+			if err != nil {
+				return CustomResourceDump{}, err
+			}
+			dump := CustomResourceDump{Group: group, Version: gv.Version, Kind: resource.Kind, Resource: resource.Name}
+			sort.Slice(list.Items, func(i, j int) bool {
+				a, b := list.Items[i], list.Items[j]
+				return lessNamespacedName(a.GetNamespace(), a.GetName(), b.GetNamespace(), b.GetName())
+			})
+			for _, object := range list.Items {
+				unstructured.RemoveNestedField(object.Object, "metadata", "managedFields")
+				dump.Items = append(dump.Items, object.Object)
+			}
+			return dump, nil
+		}
+	}
+	return CustomResourceDump{}, &ResourceError{Resource: groupKind, Err: fmt.Errorf("kind %s not found in group %q", kind, group)}
+}
+
+// GetHelmCharts lists the HelmCharts RKE2 and K3s install their bundled components, e.g. the CNI and ingress
+// controller, and any additional charts with.
+func (k *KubeConfig) GetHelmCharts(ctx context.Context) ([]HelmChartItem, error) {
+	list, err := listAll(ctx, k, helmChartResource.Resource, k.dynamic.Resource(helmChartResource).List)
+	if err != nil {
+		return nil, err
+	}
+
+	var charts []HelmChartItem
+	for _, chart := range list.Items {
+		item := HelmChartItem{Name: chart.GetName(), Namespace: chart.GetNamespace()}
+		item.Chart, _, _ = unstructured.NestedString(chart.Object, "spec", "chart")
+		item.Version, _, _ = unstructured.NestedString(chart.Object, "spec", "version")
+		item.Repo, _, _ = unstructured.NestedString(chart.Object, "spec", "repo")
+		item.TargetNamespace, _, _ = unstructured.NestedString(chart.Object, "spec", "targetNamespace")
+		charts = append(charts, item)
+	}
+	sort.Slice(charts, func(i, j int) bool {
+		return lessNamespacedName(charts[i].Namespace, charts[i].Name, charts[j].Namespace, charts[j].Name)
+	})
+	return charts, nil
+}
+
+// GetClusterVersions lists the ClusterVersion of an OpenShift cluster, with the version it is updating or was last
+// updated to, its update channel and its update history.
+func (k *KubeConfig) GetClusterVersions(ctx context.Context) ([]ClusterVersionItem, error) {
+	list, err := listAll(ctx, k, clusterVersionResource.Resource, k.dynamic.Resource(clusterVersionResource).List)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []ClusterVersionItem
+	for _, clusterVersion := range list.Items {
+		item := ClusterVersionItem{Name: clusterVersion.GetName()}
+		item.Version, _, _ = unstructured.NestedString(clusterVersion.Object, "status", "desired", "version")
+		item.Channel, _, _ = unstructured.NestedString(clusterVersion.Object, "spec", "channel")
+		item.ClusterID, _, _ = unstructured.NestedString(clusterVersion.Object, "spec", "clusterID")
+		history, _, _ := unstructured.NestedSlice(clusterVersion.Object, "status", "history")
+		for _, entry := range history {
+			update, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var historyItem ClusterVersionUpdate
+			historyItem.Version, _, _ = unstructured.NestedString(update, "version")
+			historyItem.State, _, _ = unstructured.NestedString(update, "state")
+			historyItem.StartedTime, _, _ = unstructured.NestedString(update, "startedTime")
+			item.History = append(item.History, historyItem)
+		}
+		versions = append(versions, item)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Name < versions[j].Name
+	})
+	return versions, nil
+}
@@ -0,0 +1,193 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Severity of a diagnostic. Warnings do not affect the report, errors mean a section is missing or incomplete and
+// fatal means no cluster information could be collected at all.
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+	SeverityFatal   Severity = "fatal"
+)
+
+// ErrorClass groups diagnostics by cause, so that reports and scripts can tell missing permissions from timeouts.
+type ErrorClass string
+
+const (
+	ClassForbidden   ErrorClass = "forbidden"
+	ClassNotFound    ErrorClass = "notfound"
+	ClassTimeout     ErrorClass = "timeout"
+	ClassInterrupted ErrorClass = "interrupted"
+	ClassOther       ErrorClass = "other"
+)
+
+// Exit codes derived from the diagnostics of a report.
+const (
+	ExitOK      = 0 // complete report, possibly with warnings
+	ExitFatal   = 1 // no report, e.g. the cluster is unreachable
+	ExitPartial = 2 // report with missing or incomplete sections
+)
+
+// Clock returns the time diagnostics are stamped with. It is replaced to produce reproducible reports.
+var Clock = time.Now
+
+// Diagnostic is a problem found while collecting a report.
+type Diagnostic struct {
+	Section   string     `json:"section,omitempty"`  // report section, e.g. "Storage Classes"
+	Resource  string     `json:"resource,omitempty"` // API resource the problem occurred with, e.g. "storageclasses"
+	Severity  Severity   `json:"severity"`
+	Class     ErrorClass `json:"class"`
+	Message   string     `json:"message"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// ResourceError is an error returned by an API call for a resource, so that diagnostics can name the resource.
+type ResourceError struct {
+	Resource string
+	Err      error
+}
+
+func (e *ResourceError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ResourceError) Unwrap() error {
+	return e.Err
+}
+
+// NewDiagnostic describes err, classifying it and taking the resource from a wrapped ResourceError.
+func NewDiagnostic(section string, severity Severity, err error) Diagnostic {
+	d := Diagnostic{
+		Section:   section,
+		Severity:  severity,
+		Class:     Classify(err),
+		Message:   err.Error(),
+		Timestamp: Clock(),
+	}
+	var resourceErr *ResourceError
+	if errors.As(err, &resourceErr) {
+		d.Resource = resourceErr.Resource
+	}
+	return d
+}
+
+// Classify returns the ErrorClass of err.
+func Classify(err error) ErrorClass {
+	var netErr net.Error
+	switch {
+	case apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err):
+		return ClassForbidden
+	case apierrors.IsNotFound(err):
+		return ClassNotFound
+	case errors.Is(err, context.DeadlineExceeded) || apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ClassInterrupted
+	}
+	return ClassOther
+}
+
+// Diagnostics are all problems found while collecting a report, in the order they were added.
+type Diagnostics []Diagnostic
+
+// Add appends a diagnostic for err in section and reports whether it was added, which is the case for every
+// non-nil err.
+func (d *Diagnostics) Add(section string, severity Severity, err error) bool {
+	if err == nil {
+		return false
+	}
+	*d = append(*d, NewDiagnostic(section, severity, err))
+	return true
+}
+
+// Fatal reports whether any diagnostic is fatal.
+func (d Diagnostics) Fatal() bool {
+	return d.Count(SeverityFatal) > 0
+}
+
+// HasErrors reports whether any diagnostic is an error or fatal.
+func (d Diagnostics) HasErrors() bool {
+	return d.Count(SeverityError) > 0 || d.Fatal()
+}
+
+// Count returns the number of diagnostics with severity.
+func (d Diagnostics) Count(severity Severity) int {
+	count := 0
+	for _, diagnostic := range d {
+		if diagnostic.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// ExitCode derives the process exit code from the most severe diagnostic.
+func (d Diagnostics) ExitCode() int {
+	switch {
+	case d.Fatal():
+		return ExitFatal
+	case d.HasErrors():
+		return ExitPartial
+	}
+	return ExitOK
+}
+
+// Warnings collects the problems that did not stop the collection of a section, such as retried calls and restarted
+// lists. API calls record them in the Warnings of their context, see WithWarnings.
+type Warnings struct {
+	section string
+	mu      sync.Mutex // guards items, which are added by concurrent calls
+	items   Diagnostics
+}
+
+// warningsKey is the context key of the *Warnings of a section.
+type warningsKey struct{}
+
+// WithWarnings returns a context under which API calls record their warnings for section, and the Warnings they
+// are recorded in.
+func WithWarnings(ctx context.Context, section string) (context.Context, *Warnings) {
+	warnings := &Warnings{section: section}
+	return context.WithValue(ctx, warningsKey{}, warnings), warnings
+}
+
+// Diagnostics returns the recorded warnings sorted by resource and message, so that their order does not depend on
+// the scheduling of concurrent calls.
+func (w *Warnings) Diagnostics() Diagnostics {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	items := append(Diagnostics(nil), w.items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Resource != items[j].Resource {
+			return items[i].Resource < items[j].Resource
+		}
+		return items[i].Message < items[j].Message
+	})
+	return items
+}
+
+// warn records a problem with resource that did not stop collection, such as a restarted list, in the Warnings of
+// ctx. Calls made outside of WithWarnings record nothing.
+func warn(ctx context.Context, resource string, err error) {
+	warnings, ok := ctx.Value(warningsKey{}).(*Warnings)
+	if !ok {
+		return
+	}
+	warning := NewDiagnostic(warnings.section, SeverityWarning, err)
+	warning.Resource = resource
+
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+	warnings.items = append(warnings.items, warning)
+}
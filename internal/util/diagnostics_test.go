@@ -0,0 +1,49 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []string
+		want      []string
+	}{
+		{"none", nil, nil},
+		{"sorted by resource", []string{"pods", "deployments", "configmaps"}, []string{"configmaps", "deployments", "pods"}},
+		{"same resource", []string{"pods", "pods"}, []string{"pods", "pods"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, warnings := WithWarnings(context.Background(), "Workloads")
+			var wg sync.WaitGroup
+			for _, resource := range tt.resources {
+				wg.Add(1)
+				go func(resource string) {
+					defer wg.Done()
+					warn(ctx, resource, errors.New("retried"))
+				}(resource)
+			}
+			wg.Wait()
+
+			got := warnings.Diagnostics()
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d warnings, want %d", len(got), len(tt.want))
+			}
+			for i, warning := range got {
+				if warning.Resource != tt.want[i] || warning.Section != "Workloads" || warning.Severity != SeverityWarning {
+					t.Errorf("warning %d = %+v, want a warning for %s in Workloads", i, warning, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWarnOutsideSection(t *testing.T) {
+	// must not panic without Warnings in the context
+	warn(context.Background(), "pods", errors.New("retried"))
+}
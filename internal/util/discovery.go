@@ -0,0 +1,78 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// discoveryGet requests path from the discovery endpoints of the API server and decodes the response into into.
+// Unlike the methods of the discovery client, which do not take a context, it gives up once ctx is done or
+// --call-timeout has passed, and retries transient errors.
+func (k *KubeConfig) discoveryGet(ctx context.Context, path string, into interface{}) error {
+	return k.call(ctx, "discovery", func(ctx context.Context) error {
+		body, err := k.clientset.Discovery().RESTClient().Get().AbsPath(path).Do(ctx).Raw()
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(body, into)
+	})
+}
+
+// serverGroups returns the API groups the server serves, the core group first.
+func (k *KubeConfig) serverGroups(ctx context.Context) ([]metav1.APIGroup, error) {
+	var coreVersions metav1.APIVersions
+	if err := k.discoveryGet(ctx, "/api", &coreVersions); err != nil {
+		return nil, err
+	}
+	var groupList metav1.APIGroupList
+	if err := k.discoveryGet(ctx, "/apis", &groupList); err != nil {
+		return nil, err
+	}
+
+	var core metav1.APIGroup
+	for _, version := range coreVersions.Versions {
+		core.Versions = append(core.Versions, metav1.GroupVersionForDiscovery{GroupVersion: version, Version: version})
+	}
+	if len(core.Versions) > 0 {
+		core.PreferredVersion = core.Versions[0]
+	}
+	return append([]metav1.APIGroup{core}, groupList.Groups...), nil
+}
+
+// serverResources returns the resources of each of the group versions. Group versions that cannot be discovered,
+// typically aggregated APIs whose backend is unavailable, are left out and named in an error for which
+// discovery.IsGroupDiscoveryFailedError is true.
+func (k *KubeConfig) serverResources(ctx context.Context, groupVersions []string) ([]*metav1.APIResourceList, error) {
+	var resourceLists []*metav1.APIResourceList
+	failed := map[schema.GroupVersion]error{}
+	for _, groupVersion := range groupVersions {
+		gv, err := schema.ParseGroupVersion(groupVersion)
+		if err != nil {
+			return resourceLists, err
+		}
+		path := "/apis/" + groupVersion
+		if gv.Group == "" {
+			path = "/api/" + gv.Version
+		}
+
+		resourceList := &metav1.APIResourceList{}
+		if err := k.discoveryGet(ctx, path, resourceList); err != nil {
+			if ctx.Err() != nil {
+				return resourceLists, err
+			}
+			failed[gv] = err
+			continue
+		}
+		// the group version is not always set in the response
+		resourceList.GroupVersion = groupVersion
+		resourceLists = append(resourceLists, resourceList)
+	}
+	if len(failed) > 0 {
+		return resourceLists, &discovery.ErrGroupDiscoveryFailed{Groups: failed}
+	}
+	return resourceLists, nil
+}
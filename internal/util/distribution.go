@@ -0,0 +1,262 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Kubernetes distributions DetectDistribution can identify.
+const (
+	DistributionOpenShift = "OpenShift"
+	DistributionEKS       = "EKS"
+	DistributionAKS       = "AKS"
+	DistributionGKE       = "GKE"
+	DistributionRKE2      = "RKE2"
+	DistributionK3s       = "K3s"
+	DistributionK0s       = "k0s"
+	DistributionTalos     = "Talos"
+	DistributionMicroK8s  = "MicroK8s"
+	DistributionKind      = "kind"
+	DistributionKubeadm   = "kubeadm"
+	DistributionUnknown   = "unknown"
+)
+
+// Distribution is the Kubernetes distribution a cluster runs, and what the detection is based on.
+type Distribution struct {
+	Name     string   `json:"name"`               // one of the Distribution constants
+	Provider string   `json:"provider,omitempty"` // scheme of the node providerIDs, e.g. aws, azure, gce or vsphere
+	Evidence []string `json:"evidence,omitempty"` // e.g. "kubelet version v1.27.3+rke2r1"
+	// ClusterName is the name the distribution gave the cluster on its nodes, e.g. the eksctl cluster name label,
+	// if it records one
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
+// NodeArgsAnnotation returns the node annotation RKE2 and K3s record the server or agent arguments in, or "" for
+// other distributions.
+func (d Distribution) NodeArgsAnnotation() string {
+	switch d.Name {
+	case DistributionRKE2:
+		return "rke2.io/node-args"
+	case DistributionK3s:
+		return "k3s.io/node-args"
+	}
+	return ""
+}
+
+// ClusterSignals is what DetectDistribution looks at besides the nodes.
+type ClusterSignals struct {
+	Namespaces []string // names of all namespaces
+	ConfigMaps []string // names of the ConfigMaps in kube-system
+	StaticPods []string // names of the mirror pods of static pods in kube-system
+}
+
+// distributionRules are checked in order, the first one with any evidence wins. Managed services come first since
+// their nodes can look like any self-managed distribution, and kubeadm comes last since kind and others build on it.
+// clusterName, if set, reads the cluster name from the nodes of the distribution.
+var distributionRules = []struct {
+	name        string
+	check       func(nodes NodeInfo, signals ClusterSignals) []string
+	clusterName func(nodes NodeInfo) string
+}{
+	{DistributionOpenShift, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(namespaceNamed(signals, "openshift-kube-apiserver"), nodeLabelPrefix(nodes, "node.openshift.io/"))
+	}, nil},
+	{DistributionEKS, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(kubeletVersionContains(nodes, "-eks-"), nodeLabelPrefix(nodes, "eks.amazonaws.com/"))
+	}, func(nodes NodeInfo) string {
+		return nodeLabelValue(nodes, "alpha.eksctl.io/cluster-name")
+	}},
+	{DistributionAKS, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(nodeLabelPrefix(nodes, "kubernetes.azure.com/"))
+	}, nil},
+	{DistributionGKE, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(kubeletVersionContains(nodes, "-gke."), nodeLabelPrefix(nodes, "cloud.google.com/gke-"))
+	}, nil},
+	{DistributionRKE2, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(kubeletVersionContains(nodes, "+rke2"), nodeAnnotation(nodes, "rke2.io/node-args"))
+	}, nil},
+	{DistributionK3s, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(kubeletVersionContains(nodes, "+k3s"), nodeAnnotation(nodes, "k3s.io/node-args"))
+	}, nil},
+	{DistributionK0s, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(kubeletVersionContains(nodes, "+k0s"), nodeLabelPrefix(nodes, "node.k0sproject.io/"))
+	}, nil},
+	{DistributionTalos, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(osImagePrefix(nodes, "Talos"))
+	}, nil},
+	{DistributionMicroK8s, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(nodeLabelPrefix(nodes, "microk8s.io/"))
+	}, nil},
+	{DistributionKind, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(providerIDPrefix(nodes, "kind://"))
+	}, func(nodes NodeInfo) string {
+		// kind://PROVIDER/CLUSTER/NODE
+		for _, node := range nodes.Items {
+			if parts := strings.Split(strings.TrimPrefix(node.ProviderID, "kind://"), "/"); len(parts) == 3 {
+				return parts[1]
+			}
+		}
+		return ""
+	}},
+	{DistributionKubeadm, func(nodes NodeInfo, signals ClusterSignals) []string {
+		return evidence(
+			configMapNamed(signals, "kubeadm-config"),
+			nodeAnnotation(nodes, "kubeadm.alpha.kubernetes.io/cri-socket"),
+			staticPodPrefix(signals, "kube-apiserver-"),
+		)
+	}, nil},
+}
+
+// DetectDistribution identifies the distribution from node labels, annotations, providerIDs, kubelet version
+// suffixes and OS images, and from well-known namespaces, ConfigMaps and static pods, and reads the cluster name
+// where the distribution records one.
+func DetectDistribution(nodes NodeInfo, signals ClusterSignals) Distribution {
+	distribution := Distribution{Name: DistributionUnknown}
+	for _, node := range nodes.Items {
+		if scheme, _, ok := strings.Cut(node.ProviderID, "://"); ok {
+			distribution.Provider = scheme
+			break
+		}
+	}
+	for _, rule := range distributionRules {
+		if found := rule.check(nodes, signals); len(found) > 0 {
+			distribution.Name = rule.name
+			distribution.Evidence = found
+			if rule.clusterName != nil {
+				distribution.ClusterName = rule.clusterName(nodes)
+			}
+			break
+		}
+	}
+	return distribution
+}
+
+// GetDistribution detects the distribution of the cluster the nodes belong to. Signals that cannot be listed are
+// left out: forbidden ones are reported by the permission preflight, others are recorded as warnings. An error is
+// only returned when ctx is done.
+func (k *KubeConfig) GetDistribution(ctx context.Context, nodes NodeInfo) (Distribution, error) {
+	var signals ClusterSignals
+	signal := func(resource string, err error) {
+		if err != nil && !apierrors.IsForbidden(err) {
+			warn(ctx, resource, fmt.Errorf("distribution detection is based on incomplete information: %w", err))
+		}
+	}
+
+	namespaces, err := listAll(ctx, k, "namespaces", k.clientset.CoreV1().Namespaces().List)
+	signal("namespaces", err)
+	for _, namespace := range namespaces.Items {
+		signals.Namespaces = append(signals.Namespaces, namespace.Name)
+	}
+
+	configMaps, err := listAll(ctx, k, "configmaps", k.clientset.CoreV1().ConfigMaps("kube-system").List)
+	signal("configmaps", err)
+	for _, configMap := range configMaps.Items {
+		signals.ConfigMaps = append(signals.ConfigMaps, configMap.Name)
+	}
+
+	pods, err := listAll(ctx, k, "pods", k.clientset.CoreV1().Pods("kube-system").List)
+	signal("pods", err)
+	for _, pod := range pods.Items {
+		if _, ok := pod.Annotations["kubernetes.io/config.mirror"]; ok {
+			signals.StaticPods = append(signals.StaticPods, pod.Name)
+		}
+	}
+
+	return DetectDistribution(nodes, signals), ctx.Err()
+}
+
+func evidence(found ...string) []string {
+	var result []string
+	for _, f := range found {
+		if f != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+func kubeletVersionContains(nodes NodeInfo, s string) string {
+	for _, node := range nodes.Items {
+		if strings.Contains(node.System.KubeletVersion, s) {
+			return "kubelet version " + node.System.KubeletVersion
+		}
+	}
+	return ""
+}
+
+func nodeLabelPrefix(nodes NodeInfo, prefix string) string {
+	for _, node := range nodes.Items {
+		for key := range node.Labels {
+			if strings.HasPrefix(key, prefix) {
+				return "node label " + prefix + "*"
+			}
+		}
+	}
+	return ""
+}
+
+func nodeLabelValue(nodes NodeInfo, key string) string {
+	for _, node := range nodes.Items {
+		if value := node.Labels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func nodeAnnotation(nodes NodeInfo, key string) string {
+	for _, node := range nodes.Items {
+		if _, ok := node.Annotations[key]; ok {
+			return "node annotation " + key
+		}
+	}
+	return ""
+}
+
+func providerIDPrefix(nodes NodeInfo, prefix string) string {
+	for _, node := range nodes.Items {
+		if strings.HasPrefix(node.ProviderID, prefix) {
+			return "node providerID " + node.ProviderID
+		}
+	}
+	return ""
+}
+
+func osImagePrefix(nodes NodeInfo, prefix string) string {
+	for _, node := range nodes.Items {
+		if strings.HasPrefix(node.System.OSImage, prefix) {
+			return "node OS image " + node.System.OSImage
+		}
+	}
+	return ""
+}
+
+func namespaceNamed(signals ClusterSignals, name string) string {
+	for _, namespace := range signals.Namespaces {
+		if namespace == name {
+			return "namespace " + name
+		}
+	}
+	return ""
+}
+
+func configMapNamed(signals ClusterSignals, name string) string {
+	for _, configMap := range signals.ConfigMaps {
+		if configMap == name {
+			return "ConfigMap kube-system/" + name
+		}
+	}
+	return ""
+}
+
+func staticPodPrefix(signals ClusterSignals, prefix string) string {
+	for _, pod := range signals.StaticPods {
+		if strings.HasPrefix(pod, prefix) {
+			return "static pod kube-system/" + pod
+		}
+	}
+	return ""
+}
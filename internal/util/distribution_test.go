@@ -0,0 +1,95 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestDetectDistribution(t *testing.T) {
+	node := func(item NodeItem) NodeInfo {
+		item.Name = "n1"
+		return NodeInfo{Items: []NodeItem{item}}
+	}
+	kubelet := func(version string) NodeInfo {
+		return node(NodeItem{System: v1.NodeSystemInfo{KubeletVersion: version}})
+	}
+
+	tests := []struct {
+		name    string
+		nodes   NodeInfo
+		signals ClusterSignals
+		want    Distribution
+	}{
+		{
+			name:    "OpenShift",
+			nodes:   kubelet("v1.26.5+7d22122"),
+			signals: ClusterSignals{Namespaces: []string{"default", "openshift-kube-apiserver"}},
+			want:    Distribution{Name: DistributionOpenShift, Evidence: []string{"namespace openshift-kube-apiserver"}},
+		},
+		{
+			name:  "EKS with eksctl",
+			nodes: node(NodeItem{ProviderID: "aws:///eu-west-1a/i-0abc", Labels: map[string]string{"alpha.eksctl.io/cluster-name": "prod"}, System: v1.NodeSystemInfo{KubeletVersion: "v1.27.4-eks-8ccc7ba"}}),
+			want:  Distribution{Name: DistributionEKS, Provider: "aws", Evidence: []string{"kubelet version v1.27.4-eks-8ccc7ba"}, ClusterName: "prod"},
+		},
+		{
+			name:  "AKS",
+			nodes: node(NodeItem{ProviderID: "azure:///subscriptions/x", Labels: map[string]string{"kubernetes.azure.com/cluster": "MC_rg_prod_westeurope"}}),
+			want:  Distribution{Name: DistributionAKS, Provider: "azure", Evidence: []string{"node label kubernetes.azure.com/*"}},
+		},
+		{
+			name:  "GKE",
+			nodes: kubelet("v1.27.3-gke.100"),
+			want:  Distribution{Name: DistributionGKE, Evidence: []string{"kubelet version v1.27.3-gke.100"}},
+		},
+		{
+			name:  "RKE2",
+			nodes: node(NodeItem{Annotations: map[string]string{"rke2.io/node-args": "[]"}, System: v1.NodeSystemInfo{KubeletVersion: "v1.27.3+rke2r1"}}),
+			want:  Distribution{Name: DistributionRKE2, Evidence: []string{"kubelet version v1.27.3+rke2r1", "node annotation rke2.io/node-args"}},
+		},
+		{
+			name:  "K3s",
+			nodes: kubelet("v1.27.3+k3s1"),
+			want:  Distribution{Name: DistributionK3s, Evidence: []string{"kubelet version v1.27.3+k3s1"}},
+		},
+		{
+			name:  "k0s",
+			nodes: kubelet("v1.27.3+k0s"),
+			want:  Distribution{Name: DistributionK0s, Evidence: []string{"kubelet version v1.27.3+k0s"}},
+		},
+		{
+			name:  "Talos",
+			nodes: node(NodeItem{System: v1.NodeSystemInfo{OSImage: "Talos (v1.5.0)"}}),
+			want:  Distribution{Name: DistributionTalos, Evidence: []string{"node OS image Talos (v1.5.0)"}},
+		},
+		{
+			name:  "MicroK8s",
+			nodes: node(NodeItem{Labels: map[string]string{"microk8s.io/cluster": "true"}}),
+			want:  Distribution{Name: DistributionMicroK8s, Evidence: []string{"node label microk8s.io/*"}},
+		},
+		{
+			name:    "kind before kubeadm",
+			nodes:   node(NodeItem{ProviderID: "kind://docker/dev/dev-control-plane"}),
+			signals: ClusterSignals{ConfigMaps: []string{"kubeadm-config"}},
+			want:    Distribution{Name: DistributionKind, Provider: "kind", Evidence: []string{"node providerID kind://docker/dev/dev-control-plane"}, ClusterName: "dev"},
+		},
+		{
+			name:    "kubeadm",
+			signals: ClusterSignals{ConfigMaps: []string{"kubeadm-config"}, StaticPods: []string{"kube-apiserver-cp1"}},
+			want:    Distribution{Name: DistributionKubeadm, Evidence: []string{"ConfigMap kube-system/kubeadm-config", "static pod kube-system/kube-apiserver-cp1"}},
+		},
+		{
+			name:  "unknown",
+			nodes: kubelet("v1.27.3"),
+			want:  Distribution{Name: DistributionUnknown},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectDistribution(tt.nodes, tt.signals); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectDistribution() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
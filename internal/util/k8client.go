@@ -2,25 +2,35 @@ package util
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
-	"github.com/wrkode/kasba/internal/nodeinfo"
-	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
+	"k8s.io/client-go/util/flowcontrol"
 )
 
-var kubeconfigFlag = flag.String("kubeconfig", "", "(optional) absolute path to the kubeconfig file")
 var VersionFlag = flag.Bool("version", false, "print version information and exit")
+var AllContextsFlag = flag.Bool("all-contexts", false, "assess every context in the kubeconfig and write one report per context to --output-dir")
+var OutputFlag = flag.String("output", "text", "comma separated output formats: text, json, yaml, html, markdown, cyclonedx or spdx")
+var OutputFileFlag = flag.String("output-file", "", "(optional) write the report to this file instead of stdout")
+var OutputDirFlag = flag.String("output-dir", "", "(optional) write one file per output format to this directory")
+var OutputNameFlag = flag.String("output-name", "report", "file name template for --output-dir, without extension; supports {{ .Cluster }}, {{ .Context }}, {{ .Timestamp }} and {{ .Format }}")
+var TemplateFlag = flag.String("template", "", "(optional) path to a text/template file used instead of the built-in report layout")
+var TimeoutFlag = flag.Duration("timeout", 0, "(optional) time limit for collecting a report, e.g. 5m; sections not collected in time are marked incomplete")
+var CallTimeoutFlag = flag.Duration("call-timeout", time.Minute, "time limit for each API call; 0 means no limit")
+var IncludeSectionsFlag = flag.String("include-sections", "", "(optional) comma separated sections to collect, e.g. workloads,storage-classes; all if empty")
+var ExcludeSectionsFlag = flag.String("exclude-sections", "", "(optional) comma separated sections not to collect, e.g. configmaps,container-images")
+var ParallelismFlag = flag.Int("parallelism", 4, "number of resource sections collected concurrently")
+var TemplateDirFlag = flag.String("template-dir", "", "(optional) directory with *.tmpl partials available to --template")
 
 func (a *WorkloadInfo) Add(namespace string, appType string, name string) {
 	if len(a.Namespaces) == 0 {
@@ -69,29 +79,52 @@ func (a *WorkloadInfo) Add(namespace string, appType string, name string) {
 	}
 }
 
-// GetKubeConfigPath gathers/uses --kubeconfig, KUBECONFIG EnvVar or active kubeconfig context from ~/.kube/config
+// ContextName returns the kubeconfig context the client was built for, or "" for the in-cluster config.
+func (k *KubeConfig) ContextName() string {
+	return k.contextName
+}
+
+// ClusterName returns the name of the kubeconfig cluster entry the client was built for, or "" for the in-cluster
+// config.
+func (k *KubeConfig) ClusterName() string {
+	return k.clusterName
+}
+
+// GetKubeConfigPath builds the client config with the standard kubectl loading rules: --kubeconfig, the merged
+// KUBECONFIG EnvVar file list or ~/.kube/config, falling back to the in-cluster config. The context is taken from
+// KubeConfig.Context, --context or the kubeconfig's current context, in that order, and the kubectl override flags
+// (--server, --token, --as, ...) are applied on top.
 func (k *KubeConfig) GetKubeConfigPath() error {
-	var err error
-
-	// If the flag is set, it takes the highest priority.
-	if *kubeconfigFlag != "" {
-		k.config, err = clientcmd.BuildConfigFromFlags("", *kubeconfigFlag)
-	} else if envKubeconfig := os.Getenv("KUBECONFIG"); envKubeconfig != "" {
-		// If the KUBECONFIG environment variable is set, use it.
-		k.config, err = clientcmd.BuildConfigFromFlags("", envKubeconfig)
-	} else {
-		// Otherwise, fall back to the default kubeconfig location.
-		if home := homedir.HomeDir(); home != "" {
-			k.config, err = clientcmd.BuildConfigFromFlags("", filepath.Join(home, ".kube", "config"))
-		} else {
-			k.config, err = rest.InClusterConfig()
-		}
+	overrides, err := configOverrides(k.Context)
+	if err != nil {
+		return err
+	}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules(), overrides)
+	k.config, err = clientConfig.ClientConfig()
+	raw, rawErr := clientConfig.RawConfig()
+	if rawErr == nil {
+		k.contextName = raw.CurrentContext
+	}
+	if overrides.CurrentContext != "" {
+		k.contextName = overrides.CurrentContext
+	}
+	if kubeContext, ok := raw.Contexts[k.contextName]; ok {
+		k.clusterName = kubeContext.Cluster
+	}
+	if overrides.Context.Cluster != "" {
+		k.clusterName = overrides.Context.Cluster
 	}
 
 	// handle config error
 	if err != nil {
 		return fmt.Errorf("failed to build config: %v", err)
 	}
+	k.config.QPS = float32(*qpsFlag)
+	k.config.Burst = *burstFlag
+	// one limiter shared by the clientset, dynamic and metadata clients, each would create its own otherwise
+	if k.config.QPS > 0 {
+		k.config.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(k.config.QPS, k.config.Burst)
+	}
 
 	// creates the clientset
 	k.clientset, err = kubernetes.NewForConfig(k.config)
@@ -101,13 +134,31 @@ func (k *KubeConfig) GetKubeConfigPath() error {
 		return fmt.Errorf("failed to create clientset: %v", err)
 	}
 
+	// dynamic and metadata clients for custom resources, which have no typed client
+	k.dynamic, err = dynamic.NewForConfig(k.config)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %v", err)
+	}
+	k.metadata, err = metadata.NewForConfig(k.config)
+	if err != nil {
+		return fmt.Errorf("failed to create metadata client: %v", err)
+	}
+
 	return nil
 }
 
+// lessNamespacedName orders objects by namespace first and name second, matching how the report groups them.
+func lessNamespacedName(namespaceA, nameA, namespaceB, nameB string) bool {
+	if namespaceA != namespaceB {
+		return namespaceA < namespaceB
+	}
+	return nameA < nameB
+}
+
 // NamespaceExists checks if the given namespace exists in the cluster.
-func (k *KubeConfig) NamespaceExists(namespaceName string) (bool, error) {
+func (k *KubeConfig) NamespaceExists(ctx context.Context, namespaceName string) (bool, error) {
 	// get namespaces
-	namespaces, err := k.clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	namespaces, err := listAll(ctx, k, "namespaces", k.clientset.CoreV1().Namespaces().List)
 	if err != nil {
 		return false, fmt.Errorf("failed to get namespaces: %v", err)
 	}
@@ -122,116 +173,219 @@ func (k *KubeConfig) NamespaceExists(namespaceName string) (bool, error) {
 	return false, nil
 }
 
+// addWorkload appends to the workload list; the workload listers run concurrently.
+func (k *KubeConfig) addWorkload(item WorkloadListItem) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.workloadlist = append(k.workloadlist, item)
+}
+
 // GetDeployments lists the deployments in all namespaces and returns them with NAMES and NAMESPACE.
-func (k *KubeConfig) GetDeployments() {
-	list, _ := k.clientset.AppsV1().Deployments(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetDeployments(ctx context.Context) error {
+	list, err := listAll(ctx, k, "deployments", k.clientset.AppsV1().Deployments(metav1.NamespaceAll).List)
+	if err != nil {
+		return err
+	}
+
 	for _, listItem := range list.Items {
-		k.workloadlist = append(k.workloadlist, WorkloadListItem{
+		k.addWorkload(WorkloadListItem{
 			Name:      listItem.Name,
 			Namespace: listItem.Namespace,
 			Type:      "Deployments",
 		})
 	}
+	return nil
 }
 
 // GetDaemonSets lists the daemonsets in all namespaces and returns them with NAMES and NAMESPACE.
-func (k *KubeConfig) GetDaemonSets() {
-	list, _ := k.clientset.AppsV1().DaemonSets(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetDaemonSets(ctx context.Context) error {
+	list, err := listAll(ctx, k, "daemonsets", k.clientset.AppsV1().DaemonSets(metav1.NamespaceAll).List)
+	if err != nil {
+		return err
+	}
+
 	for _, listItem := range list.Items {
-		k.workloadlist = append(k.workloadlist, WorkloadListItem{
+		k.addWorkload(WorkloadListItem{
 			Name:      listItem.Name,
 			Namespace: listItem.Namespace,
 			Type:      "DaemonSets",
 		})
 	}
+	return nil
 }
 
 // GetStatefulSets lists the statefulsets in all namespaces and returns them with NAMES and NAMESPACE.
-func (k *KubeConfig) GetStatefulSets() {
-	list, _ := k.clientset.AppsV1().StatefulSets(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetStatefulSets(ctx context.Context) error {
+	list, err := listAll(ctx, k, "statefulsets", k.clientset.AppsV1().StatefulSets(metav1.NamespaceAll).List)
+	if err != nil {
+		return err
+	}
+
 	for _, listItem := range list.Items {
-		k.workloadlist = append(k.workloadlist, WorkloadListItem{
+		k.addWorkload(WorkloadListItem{
 			Name:      listItem.Name,
 			Namespace: listItem.Namespace,
 			Type:      "StatefulSets",
 		})
 	}
+	return nil
 }
 
 // GetWorkloads List all the apps running on the cluster, sorted by namespace and type
-func (k *KubeConfig) GetWorkloads() (WorkloadInfo, error) {
+func (k *KubeConfig) GetWorkloads(ctx context.Context) (WorkloadInfo, error) {
 	var workloadInfo WorkloadInfo
-	k.GetDeployments()
-	k.GetDaemonSets()
-	k.GetStatefulSets()
+	listers := []func(context.Context) error{k.GetDeployments, k.GetDaemonSets, k.GetStatefulSets}
+	errs := make([]error, len(listers))
+	var wg sync.WaitGroup
+	for i, list := range listers {
+		wg.Add(1)
+		go func(i int, list func(context.Context) error) {
+			defer wg.Done()
+			errs[i] = list(ctx)
+		}(i, list)
+	}
+	wg.Wait()
+	sort.SliceStable(k.workloadlist, func(i, j int) bool {
+		if k.workloadlist[i].Namespace != k.workloadlist[j].Namespace {
+			return k.workloadlist[i].Namespace < k.workloadlist[j].Namespace
+		}
+		if k.workloadlist[i].Type != k.workloadlist[j].Type {
+			return k.workloadlist[i].Type < k.workloadlist[j].Type
+		}
+		return k.workloadlist[i].Name < k.workloadlist[j].Name
+	})
 	for _, a := range k.workloadlist {
 		workloadInfo.Add(a.Namespace, a.Type, a.Name)
 	}
+	for _, err := range errs {
+		if err != nil {
+			return workloadInfo, err
+		}
+	}
 	return workloadInfo, nil
 
 }
 
-// GetNetworkPluginPodName determines which CNI is deployed by explicitly searching for Calico|Cilium pods. K3s Will return an error.
-func (k *KubeConfig) GetNetworkPluginPodName() (string, error) {
-	pods, err := k.clientset.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+// GetContainerImages lists the images of every container and initContainer in all namespaces, with the digest the
+// kubelet resolved and the workload owning the pod.
+func (k *KubeConfig) GetContainerImages(ctx context.Context) ([]ContainerImageItem, error) {
+	pods, err := listAll(ctx, k, "pods", k.clientset.CoreV1().Pods(metav1.NamespaceAll).List)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	for _, pod := range pods.Items {
-		if strings.Contains(pod.Name, "calico") || strings.Contains(pod.Name, "cilium") {
-			// Truncate the pod name at the first hyphen
-			truncatedName := strings.SplitN(pod.Name, "-", 2)[0]
-			return truncatedName, nil
+	// Deployments own their pods through ReplicaSets, so resolve ReplicaSet -> Deployment up front
+	replicaSets, err := listAll(ctx, k, "replicasets", k.clientset.AppsV1().ReplicaSets(metav1.NamespaceAll).List)
+	if err != nil {
+		return nil, err
+	}
+	replicaSetOwners := map[string]metav1.OwnerReference{}
+	for _, rs := range replicaSets.Items {
+		if owner := metav1.GetControllerOf(&rs); owner != nil {
+			replicaSetOwners[rs.Namespace+"/"+rs.Name] = *owner
 		}
 	}
 
-	return "", fmt.Errorf("unable to detect CNI - is this K3s")
-}
+	var images []ContainerImageItem
+	for _, pod := range pods.Items {
+		ownerKind, ownerName := "Pod", pod.Name
+		if owner := metav1.GetControllerOf(&pod); owner != nil {
+			ownerKind, ownerName = owner.Kind, owner.Name
+			if rsOwner, ok := replicaSetOwners[pod.Namespace+"/"+owner.Name]; ok && owner.Kind == "ReplicaSet" {
+				ownerKind, ownerName = rsOwner.Kind, rsOwner.Name
+			}
+		}
 
-// FetchClustersJSON fetches node information for the active context in kubeconfig and returns it as JSON.
-func (k *KubeConfig) FetchClustersJSON() ([]byte, error) {
-	nodes, err := k.clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("Error getting nodes for active context: %v", err)
+		digests := map[string]string{}
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			digests[status.Name] = imageDigest(status.ImageID)
+		}
+
+		add := func(containers []v1.Container, init bool) {
+			for _, container := range containers {
+				images = append(images, ContainerImageItem{
+					Image:         container.Image,
+					Digest:        digests[container.Name],
+					Namespace:     pod.Namespace,
+					Pod:           pod.Name,
+					Container:     container.Name,
+					InitContainer: init,
+					OwnerKind:     ownerKind,
+					OwnerName:     ownerName,
+				})
+			}
+		}
+		add(pod.Spec.InitContainers, true)
+		add(pod.Spec.Containers, false)
 	}
 
-	activeContext := k.config.Host // This will get the API Server URL instead of the context name
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Image != images[j].Image {
+			return images[i].Image < images[j].Image
+		}
+		if images[i].Namespace != images[j].Namespace || images[i].Pod != images[j].Pod {
+			return lessNamespacedName(images[i].Namespace, images[i].Pod, images[j].Namespace, images[j].Pod)
+		}
+		return images[i].Container < images[j].Container
+	})
+	return images, nil
+}
 
-	clusterNodes := ClusterNodes{
-		Cluster: activeContext,
-		Nodes:   nodes.Items,
+// imageDigest extracts the repository digest from a container status imageID such as
+// "docker-pullable://nginx@sha256:..." or "docker.io/library/nginx@sha256:...".
+func imageDigest(imageID string) string {
+	if i := strings.LastIndex(imageID, "@"); i >= 0 {
+		return imageID[i+1:]
 	}
+	return ""
+}
 
-	jsonData, err := json.MarshalIndent(clusterNodes, "", "    ")
+// GetNodeInfo lists the nodes of the cluster, sorted by name.
+func (k *KubeConfig) GetNodeInfo(ctx context.Context) (NodeInfo, error) {
+	list, err := listAll(ctx, k, "nodes", k.clientset.CoreV1().Nodes().List)
 	if err != nil {
-		return nil, fmt.Errorf("Error marshaling cluster nodes to JSON: %v", err)
+		return NodeInfo{}, fmt.Errorf("error fetching nodes: %w", err)
+	}
+
+	nodeInfo := NodeInfo{Cluster: k.config.Host}
+	for _, node := range list.Items {
+		nodeInfo.Items = append(nodeInfo.Items, NodeItem{
+			Name:          node.Name,
+			Labels:        node.Labels,
+			Annotations:   node.Annotations,
+			CreatedAt:     node.CreationTimestamp,
+			ProviderID:    node.Spec.ProviderID,
+			PodCIDR:       node.Spec.PodCIDR,
+			Unschedulable: node.Spec.Unschedulable,
+			Taints:        node.Spec.Taints,
+			Addresses:     node.Status.Addresses,
+			Capacity:      resourceQuantities(node.Status.Capacity),
+			Allocatable:   resourceQuantities(node.Status.Allocatable),
+			Conditions:    node.Status.Conditions,
+			System:        node.Status.NodeInfo,
+		})
 	}
-
-	return jsonData, nil
+	sort.Slice(nodeInfo.Items, func(i, j int) bool {
+		return nodeInfo.Items[i].Name < nodeInfo.Items[j].Name
+	})
+	return nodeInfo, nil
 }
 
-// GetNodeInfo get nodes info from active context
-func (k *KubeConfig) GetNodeInfo() (nodeinfo.NodesInfo, error) {
-	// Fetch JSON data
-	jsonData, err := k.FetchClustersJSON()
-	if err != nil {
-		return nodeinfo.NodesInfo{}, fmt.Errorf("error fetching nodes: %v", err)
+// resourceQuantities converts a resource list to plain strings, which render the same in every output format.
+func resourceQuantities(resources v1.ResourceList) map[string]string {
+	if len(resources) == 0 {
+		return nil
 	}
-	//fmt.Println(string(jsonData)) # using it for check jsonData
-	// Unmarshal the JSON data into the struct.
-	var data nodeinfo.NodesInfo
-	err = json.Unmarshal(jsonData, &data) // jsonData is already []byte, so no need to cast it again
-	if err != nil {
-		return nodeinfo.NodesInfo{}, fmt.Errorf("error unmarshalling JSON: %v", err)
+	quantities := map[string]string{}
+	for name, quantity := range resources {
+		quantities[string(name)] = quantity.String()
 	}
-
-	return data, nil
+	return quantities
 }
 
 // GetStorageClasses lists the Storage Classes in the cluster and returns them.
-func (k *KubeConfig) GetStorageClasses() ([]StorageClassItem, error) {
-	list, err := k.clientset.StorageV1().StorageClasses().List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetStorageClasses(ctx context.Context) ([]StorageClassItem, error) {
+	list, err := listAll(ctx, k, "storageclasses", k.clientset.StorageV1().StorageClasses().List)
 	if err != nil {
 		return nil, err
 	}
@@ -245,12 +399,15 @@ func (k *KubeConfig) GetStorageClasses() ([]StorageClassItem, error) {
 		}
 		storageClasses = append(storageClasses, sc)
 	}
+	sort.Slice(storageClasses, func(i, j int) bool {
+		return storageClasses[i].Name < storageClasses[j].Name
+	})
 	return storageClasses, nil
 }
 
 // GetPersistentVolumes lists the Persistent Volumes available in the cluster and returns them.
-func (k *KubeConfig) GetPersistentVolumes() ([]PersistentVolumeItem, error) {
-	list, err := k.clientset.CoreV1().PersistentVolumes().List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetPersistentVolumes(ctx context.Context) ([]PersistentVolumeItem, error) {
+	list, err := listAll(ctx, k, "persistentvolumes", k.clientset.CoreV1().PersistentVolumes().List)
 	if err != nil {
 		return nil, err
 	}
@@ -271,12 +428,15 @@ func (k *KubeConfig) GetPersistentVolumes() ([]PersistentVolumeItem, error) {
 		}
 		persistentVolumes = append(persistentVolumes, pv)
 	}
+	sort.Slice(persistentVolumes, func(i, j int) bool {
+		return persistentVolumes[i].Name < persistentVolumes[j].Name
+	})
 	return persistentVolumes, nil
 }
 
 // GetPersistentVolumeClaims lists all Persistent Volume Claims across all namespaces.
-func (k *KubeConfig) GetPersistentVolumeClaims() ([]PersistentVolumeClaimItem, error) {
-	list, err := k.clientset.CoreV1().PersistentVolumeClaims(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetPersistentVolumeClaims(ctx context.Context) ([]PersistentVolumeClaimItem, error) {
+	list, err := listAll(ctx, k, "persistentvolumeclaims", k.clientset.CoreV1().PersistentVolumeClaims(metav1.NamespaceAll).List)
 	if err != nil {
 		return nil, err
 	}
@@ -291,16 +451,19 @@ func (k *KubeConfig) GetPersistentVolumeClaims() ([]PersistentVolumeClaimItem, e
 			Capacity:     listItem.Status.Capacity[v1.ResourceStorage],
 			AccessModes:  listItem.Status.AccessModes,
 			StorageClass: *listItem.Spec.StorageClassName,
-			Age:          listItem.CreationTimestamp,
+			CreatedAt:    listItem.CreationTimestamp,
 		}
 		persistentVolumeClaims = append(persistentVolumeClaims, pvc)
 	}
+	sort.Slice(persistentVolumeClaims, func(i, j int) bool {
+		return lessNamespacedName(persistentVolumeClaims[i].Namespace, persistentVolumeClaims[i].Name, persistentVolumeClaims[j].Namespace, persistentVolumeClaims[j].Name)
+	})
 	return persistentVolumeClaims, nil
 }
 
 // GetConfigMaps lists all ConfigMaps across all namespaces.
-func (k *KubeConfig) GetConfigMaps() ([]ConfigMapItem, error) {
-	list, err := k.clientset.CoreV1().ConfigMaps(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetConfigMaps(ctx context.Context) ([]ConfigMapItem, error) {
+	list, err := listAll(ctx, k, "configmaps", k.clientset.CoreV1().ConfigMaps(metav1.NamespaceAll).List)
 	if err != nil {
 		return nil, err
 	}
@@ -311,16 +474,19 @@ func (k *KubeConfig) GetConfigMaps() ([]ConfigMapItem, error) {
 			Namespace: listItem.Namespace,
 			Name:      listItem.Name,
 			Data:      listItem.Data,
-			Age:       listItem.CreationTimestamp,
+			CreatedAt: listItem.CreationTimestamp,
 		}
 		configMaps = append(configMaps, cm)
 	}
+	sort.Slice(configMaps, func(i, j int) bool {
+		return lessNamespacedName(configMaps[i].Namespace, configMaps[i].Name, configMaps[j].Namespace, configMaps[j].Name)
+	})
 	return configMaps, nil
 }
 
 // GetAllServices lists all Services across all namespaces.
-func (k *KubeConfig) GetAllServices() ([]ServiceItem, error) {
-	svcList, err := k.clientset.CoreV1().Services(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetAllServices(ctx context.Context) ([]ServiceItem, error) {
+	svcList, err := listAll(ctx, k, "services", k.clientset.CoreV1().Services(metav1.NamespaceAll).List)
 	if err != nil {
 		return nil, err
 	}
@@ -332,11 +498,6 @@ func (k *KubeConfig) GetAllServices() ([]ServiceItem, error) {
 			externalIP = svc.Status.LoadBalancer.Ingress[0].IP
 		}
 
-		// Calculate Age in seconds
-		ageInSeconds := time.Since(svc.ObjectMeta.CreationTimestamp.Time).Seconds()
-		// Convert Age to days
-		ageInDays := int(ageInSeconds / (60 * 60 * 24)) // convert seconds to days
-
 		serviceItem := ServiceItem{
 			Namespace:  svc.Namespace,
 			Name:       svc.Name,
@@ -344,16 +505,19 @@ func (k *KubeConfig) GetAllServices() ([]ServiceItem, error) {
 			ClusterIP:  svc.Spec.ClusterIP,
 			ExternalIP: externalIP,
 			Ports:      svc.Spec.Ports,
-			Age:        ageInDays,
+			CreatedAt:  svc.CreationTimestamp,
 		}
 		services = append(services, serviceItem)
 	}
+	sort.Slice(services, func(i, j int) bool {
+		return lessNamespacedName(services[i].Namespace, services[i].Name, services[j].Namespace, services[j].Name)
+	})
 	return services, nil
 }
 
 // GetAllIngresses lists all Ingresses across all namespaces.
-func (k *KubeConfig) GetAllIngresses() ([]IngressItem, error) {
-	ingList, err := k.clientset.NetworkingV1().Ingresses(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetAllIngresses(ctx context.Context) ([]IngressItem, error) {
+	ingList, err := listAll(ctx, k, "ingresses", k.clientset.NetworkingV1().Ingresses(metav1.NamespaceAll).List)
 	if err != nil {
 		return nil, err
 	}
@@ -391,26 +555,25 @@ func (k *KubeConfig) GetAllIngresses() ([]IngressItem, error) {
 			}
 		}
 
-		// Calculate Age
-		ageInSeconds := time.Since(ing.ObjectMeta.CreationTimestamp.Time).Seconds()
-		ageInDays := int(ageInSeconds / (60 * 60 * 24))
-
 		ingressItem := IngressItem{
 			Namespace:      ing.Namespace,
 			Name:           ing.Name,
 			Hosts:          rules,
 			DefaultBackend: defaultBackend,
 			Addresses:      addresses, // Added this
-			Age:            ageInDays,
+			CreatedAt:      ing.CreationTimestamp,
 		}
 		ingresses = append(ingresses, ingressItem)
 	}
+	sort.Slice(ingresses, func(i, j int) bool {
+		return lessNamespacedName(ingresses[i].Namespace, ingresses[i].Name, ingresses[j].Namespace, ingresses[j].Name)
+	})
 	return ingresses, nil
 }
 
 // GetAllClusterRoles lists all ClusterRoles defined.
-func (k *KubeConfig) GetAllClusterRoles() ([]ClusterRoleItem, error) {
-	roles, err := k.clientset.RbacV1().ClusterRoles().List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetAllClusterRoles(ctx context.Context) ([]ClusterRoleItem, error) {
+	roles, err := listAll(ctx, k, "clusterroles", k.clientset.RbacV1().ClusterRoles().List)
 	if err != nil {
 		return nil, err
 	}
@@ -429,12 +592,15 @@ func (k *KubeConfig) GetAllClusterRoles() ([]ClusterRoleItem, error) {
 		}
 		roleItems = append(roleItems, roleItem)
 	}
+	sort.Slice(roleItems, func(i, j int) bool {
+		return roleItems[i].Name < roleItems[j].Name
+	})
 	return roleItems, nil
 }
 
 // GetAllClusterRoleBindings lists all ClusterRolesBindings defined.
-func (k *KubeConfig) GetAllClusterRoleBindings() ([]ClusterRoleBindingItem, error) {
-	crbList, err := k.clientset.RbacV1().ClusterRoleBindings().List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetAllClusterRoleBindings(ctx context.Context) ([]ClusterRoleBindingItem, error) {
+	crbList, err := listAll(ctx, k, "clusterrolebindings", k.clientset.RbacV1().ClusterRoleBindings().List)
 	if err != nil {
 		return nil, err
 	}
@@ -448,60 +614,44 @@ func (k *KubeConfig) GetAllClusterRoleBindings() ([]ClusterRoleBindingItem, erro
 		}
 		clusterRoleBindings = append(clusterRoleBindings, crbItem)
 	}
+	sort.Slice(clusterRoleBindings, func(i, j int) bool {
+		return clusterRoleBindings[i].Name < clusterRoleBindings[j].Name
+	})
 	return clusterRoleBindings, nil
 }
 
 // GetAllServiceAccounts lists all Service Accounts defined
-func (k *KubeConfig) GetAllServiceAccounts() ([]ServiceAccountItem, error) {
-	serviceAccountList, err := k.clientset.CoreV1().ServiceAccounts(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetAllServiceAccounts(ctx context.Context) ([]ServiceAccountItem, error) {
+	serviceAccountList, err := listAll(ctx, k, "serviceaccounts", k.clientset.CoreV1().ServiceAccounts(metav1.NamespaceAll).List)
 	if err != nil {
 		return nil, err
 	}
 
 	var serviceAccounts []ServiceAccountItem
 	for _, sa := range serviceAccountList.Items {
-		age := time.Since(sa.ObjectMeta.CreationTimestamp.Time)
-		ageStr := ""
-
-		// Convert age to hours and days
-		totalHours := int(age.Hours())
-		if totalHours < 24 {
-			ageStr = fmt.Sprintf("%dh", totalHours)
-		} else {
-			days := totalHours / 24
-			ageStr = fmt.Sprintf("%dd", days)
-		}
-
 		serviceAccount := ServiceAccountItem{
 			Name:      sa.Name,
 			Namespace: sa.Namespace,
 			Secrets:   len(sa.Secrets),
-			Age:       ageStr,
+			CreatedAt: sa.CreationTimestamp,
 		}
 		serviceAccounts = append(serviceAccounts, serviceAccount)
 	}
+	sort.Slice(serviceAccounts, func(i, j int) bool {
+		return lessNamespacedName(serviceAccounts[i].Namespace, serviceAccounts[i].Name, serviceAccounts[j].Namespace, serviceAccounts[j].Name)
+	})
 	return serviceAccounts, nil
 }
 
 // GetAllNetworkPolicies Implements a method to fetch all network policies
-func (k *KubeConfig) GetAllNetworkPolicies() ([]NetworkPolicyItem, error) {
-	netPolList, err := k.clientset.NetworkingV1().NetworkPolicies(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+func (k *KubeConfig) GetAllNetworkPolicies(ctx context.Context) ([]NetworkPolicyItem, error) {
+	netPolList, err := listAll(ctx, k, "networkpolicies", k.clientset.NetworkingV1().NetworkPolicies(metav1.NamespaceAll).List)
 	if err != nil {
 		return nil, err
 	}
 
 	var netPolicies []NetworkPolicyItem
 	for _, netPol := range netPolList.Items {
-		age := time.Since(netPol.ObjectMeta.CreationTimestamp.Time)
-
-		// Convert age to hours or days
-		var ageStr string
-		if age.Hours() < 24 {
-			ageStr = fmt.Sprintf("%.0fh", age.Hours())
-		} else {
-			ageStr = fmt.Sprintf("%.0fd", age.Hours()/24)
-		}
-
 		netPolItem := NetworkPolicyItem{
 			Name:        netPol.Name,
 			Namespace:   netPol.Namespace,
@@ -509,10 +659,13 @@ func (k *KubeConfig) GetAllNetworkPolicies() ([]NetworkPolicyItem, error) {
 			Ingress:     netPol.Spec.Ingress,
 			Egress:      netPol.Spec.Egress,
 			PolicyTypes: netPol.Spec.PolicyTypes,
-			Age:         ageStr,
+			CreatedAt:   netPol.CreationTimestamp,
 		}
 		netPolicies = append(netPolicies, netPolItem)
 	}
 
+	sort.Slice(netPolicies, func(i, j int) bool {
+		return lessNamespacedName(netPolicies[i].Namespace, netPolicies[i].Name, netPolicies[j].Namespace, netPolicies[j].Name)
+	})
 	return netPolicies, nil
 }
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var PageSizeFlag = flag.Int64("page-size", 500, "number of items requested per list call; 0 lists everything in one call")
+
+// maxListRestarts is how often a list is restarted after its continue token expired, before giving up.
+const maxListRestarts = 3
+
+// listResult is a typed list such as *v1.PodList.
+type listResult interface {
+	runtime.Object
+	metav1.ListInterface
+}
+
+// listAll pages through a list call with --page-size items per request and returns all items in a single list.
+// When the continue token expires between two pages, because the API server compacted its history, the list is
+// restarted from the beginning and a warning is recorded for the report.
+func listAll[L listResult](ctx context.Context, k *KubeConfig, resource string, list func(context.Context, metav1.ListOptions) (L, error)) (L, error) {
+	var result L
+	var items []runtime.Object
+	options := metav1.ListOptions{Limit: *PageSizeFlag}
+	restarts := 0
+	for {
+		page, err := listPage(ctx, k, resource, options, list)
+		if apierrors.IsResourceExpired(err) && options.Continue != "" && restarts < maxListRestarts {
+			restarts++
+			warn(ctx, resource, fmt.Errorf("continue token expired after %d items, restarted the list (attempt %d of %d)", len(items), restarts, maxListRestarts))
+			items = nil
+			options.Continue = ""
+			continue
+		}
+		if err != nil {
+			// typed clients return an empty list along with the error
+			return page, &ResourceError{Resource: resource, Err: err}
+		}
+
+		pageItems, err := meta.ExtractList(page)
+		if err != nil {
+			return page, err
+		}
+		items = append(items, pageItems...)
+		if options.Continue == "" {
+			result = page
+		}
+
+		if page.GetContinue() == "" {
+			break
+		}
+		options.Continue = page.GetContinue()
+	}
+
+	result.SetContinue("")
+	return result, meta.SetList(result, items)
+}
+
+// listPage requests a single page of a list.
+func listPage[L listResult](ctx context.Context, k *KubeConfig, resource string, options metav1.ListOptions, list func(context.Context, metav1.ListOptions) (L, error)) (L, error) {
+	var page L
+	err := k.call(ctx, resource, func(ctx context.Context) error {
+		var err error
+		page, err = list(ctx, options)
+		return err
+	})
+	return page, err
+}
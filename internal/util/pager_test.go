@@ -0,0 +1,90 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podPages returns a list func that serves pods in pages of one, continuing with the pod's name. The first expire
+// requests with a continue token fail because the token expired.
+func podPages(expire int, names ...string) func(context.Context, metav1.ListOptions) (*v1.PodList, error) {
+	return func(_ context.Context, options metav1.ListOptions) (*v1.PodList, error) {
+		if options.Continue != "" && expire > 0 {
+			expire--
+			return &v1.PodList{}, apierrors.NewResourceExpired("continue token expired")
+		}
+		i := 0
+		for options.Continue != "" && names[i] != options.Continue {
+			i++
+		}
+		if options.Continue != "" {
+			i++
+		}
+		list := &v1.PodList{Items: []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: names[i]}}}}
+		if i+1 < len(names) {
+			list.Continue = names[i]
+		}
+		return list, nil
+	}
+}
+
+func TestListAll(t *testing.T) {
+	defer func(pageSize int64) { *PageSizeFlag = pageSize }(*PageSizeFlag)
+	*PageSizeFlag = 1
+
+	tests := []struct {
+		name     string
+		expire   int
+		want     []string
+		warnings int
+		wantErr  bool
+	}{
+		{"no restart", 0, []string{"a", "b", "c"}, 0, false},
+		{"restarted once", 1, []string{"a", "b", "c"}, 1, false},
+		{"restarted up to the limit", maxListRestarts, []string{"a", "b", "c"}, maxListRestarts, false},
+		{"too many restarts", maxListRestarts + 1, nil, maxListRestarts, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, warnings := WithWarnings(context.Background(), "Workloads")
+			list, err := listAll(ctx, &KubeConfig{}, "pods", podPages(tt.expire, "a", "b", "c"))
+
+			if tt.wantErr {
+				var resourceErr *ResourceError
+				if !errors.As(err, &resourceErr) || !apierrors.IsResourceExpired(err) {
+					t.Errorf("listAll() error = %v, want an expired continue token for pods", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("listAll() error = %v", err)
+				}
+				var got []string
+				for _, pod := range list.Items {
+					got = append(got, pod.Name)
+				}
+				if !reflect.DeepEqual(got, tt.want) || list.Continue != "" {
+					t.Errorf("listAll() = %v with continue %q, want %v", got, list.Continue, tt.want)
+				}
+			}
+			if got := len(warnings.Diagnostics()); got != tt.warnings {
+				t.Errorf("got %d warnings, want %d", got, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestListAllFirstPageExpired(t *testing.T) {
+	// without a continue token there is nothing to restart
+	list := func(context.Context, metav1.ListOptions) (*v1.PodList, error) {
+		return &v1.PodList{}, apierrors.NewResourceExpired("too old resource version")
+	}
+	if _, err := listAll(context.Background(), &KubeConfig{}, "pods", list); !apierrors.IsResourceExpired(err) {
+		t.Errorf("listAll() error = %v, want the expired error", err)
+	}
+}
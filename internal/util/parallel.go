@@ -0,0 +1,24 @@
+package util
+
+import "sync"
+
+// parallel calls fn for every index below n, with at most --parallelism calls running at a time, and returns once
+// all of them are done. fn stores its results by index, so that they keep their order.
+func parallel(n int, fn func(i int)) {
+	limit := *ParallelismFlag
+	if limit < 1 {
+		limit = 1
+	}
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
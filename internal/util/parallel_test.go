@@ -0,0 +1,56 @@
+package util
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParallel(t *testing.T) {
+	tests := []struct {
+		name        string
+		n           int
+		parallelism int
+	}{
+		{"none", 0, 4},
+		{"fewer than the limit", 2, 4},
+		{"more than the limit", 20, 4},
+		{"invalid limit", 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(parallelism int) { *ParallelismFlag = parallelism }(*ParallelismFlag)
+			*ParallelismFlag = tt.parallelism
+
+			var mu sync.Mutex
+			running, maxRunning := 0, 0
+			results := make([]int, tt.n)
+			parallel(tt.n, func(i int) {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+				time.Sleep(time.Millisecond)
+				results[i] = i * i
+				mu.Lock()
+				running--
+				mu.Unlock()
+			})
+
+			for i, result := range results {
+				if result != i*i {
+					t.Errorf("result %d = %d, want %d", i, result, i*i)
+				}
+			}
+			limit := tt.parallelism
+			if limit < 1 {
+				limit = 1
+			}
+			if maxRunning > limit {
+				t.Errorf("%d calls ran at a time, want at most %d", maxRunning, limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Resource is an API resource kasba lists across all namespaces.
+type Resource struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+}
+
+// Resources are all resources kasba collects, the minimal read-only permissions it needs.
+var Resources = []Resource{
+	{Group: "", Resource: "configmaps"},
+	{Group: "", Resource: "namespaces"},
+	{Group: "", Resource: "nodes"},
+	{Group: "", Resource: "persistentvolumeclaims"},
+	{Group: "", Resource: "persistentvolumes"},
+	{Group: "", Resource: "pods"},
+	{Group: "", Resource: "serviceaccounts"},
+	{Group: "", Resource: "services"},
+	{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"},
+	{Group: "apps", Resource: "daemonsets"},
+	{Group: "apps", Resource: "deployments"},
+	{Group: "apps", Resource: "replicasets"},
+	{Group: "apps", Resource: "statefulsets"},
+	{Group: "config.openshift.io", Resource: "clusterversions"},
+	{Group: "helm.cattle.io", Resource: "helmcharts"},
+	{Group: "networking.k8s.io", Resource: "ingresses"},
+	{Group: "networking.k8s.io", Resource: "networkpolicies"},
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"},
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"},
+	{Group: "storage.k8s.io", Resource: "storageclasses"},
+}
+
+// CustomResourceRBACNote explains what ReadOnlyClusterRole leaves out, for printing along with the role.
+const CustomResourceRBACNote = `This role does not cover custom resources. Without list on their API groups, the
+Custom Resource Definitions section reports them without instance counts and --custom-resource kinds are skipped.
+Add a rule with the groups and resources to count, or apiGroups ["*"], resources ["*"] and verbs ["list"] for all.`
+
+// PermissionItem is the result of checking whether the current user may list a resource in all namespaces.
+type PermissionItem struct {
+	Resource
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Permissions is the permission matrix for Resources.
+type Permissions []PermissionItem
+
+// Denied returns the resources among resources that may not be listed. Resources that were not checked are
+// assumed to be allowed, so that a failed preflight does not skip anything.
+func (p Permissions) Denied(resources ...string) []string {
+	var denied []string
+	for _, resource := range resources {
+		for _, item := range p {
+			if item.Resource.Resource == resource && !item.Allowed {
+				denied = append(denied, resource)
+			}
+		}
+	}
+	return denied
+}
+
+// CheckPermissions asks the API server with a SelfSubjectAccessReview per resource whether the current user may
+// list it in all namespaces, --parallelism resources at a time. Resources that could not be checked are left out
+// and the first error is returned.
+func (k *KubeConfig) CheckPermissions(ctx context.Context) (Permissions, error) {
+	items := make([]PermissionItem, len(Resources))
+	errs := make([]error, len(Resources))
+	parallel(len(Resources), func(i int) {
+		items[i], errs[i] = k.checkPermission(ctx, Resources[i])
+	})
+
+	var permissions Permissions
+	var firstErr error
+	for i, err := range errs {
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		permissions = append(permissions, items[i])
+	}
+	return permissions, firstErr
+}
+
+// checkPermission asks the API server with a SelfSubjectAccessReview whether the current user may list resource in
+// all namespaces.
+func (k *KubeConfig) checkPermission(ctx context.Context, resource Resource) (PermissionItem, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "list",
+				Group:    resource.Group,
+				Resource: resource.Resource,
+			},
+		},
+	}
+	err := k.call(ctx, "selfsubjectaccessreviews", func(ctx context.Context) error {
+		var err error
+		review, err = k.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		return err
+	})
+	if err != nil {
+		return PermissionItem{}, &ResourceError{
+			Resource: "selfsubjectaccessreviews",
+			Err:      fmt.Errorf("failed to check permissions for %s: %w", resource.Resource, err),
+		}
+	}
+
+	reason := review.Status.Reason
+	if review.Status.EvaluationError != "" {
+		reason = review.Status.EvaluationError
+	}
+	return PermissionItem{Resource: resource, Allowed: review.Status.Allowed, Reason: reason}, nil
+}
+
+// ReadOnlyClusterRole returns a ClusterRole that grants list on every resource kasba collects, and nothing else.
+// Custom resources are not covered, since their groups differ from cluster to cluster; see CustomResourceRBACNote.
+func ReadOnlyClusterRole(name string) rbacv1.ClusterRole {
+	resourcesByGroup := map[string][]string{}
+	var groups []string
+	for _, resource := range Resources {
+		if _, ok := resourcesByGroup[resource.Group]; !ok {
+			groups = append(groups, resource.Group)
+		}
+		resourcesByGroup[resource.Group] = append(resourcesByGroup[resource.Group], resource.Resource)
+	}
+	sort.Strings(groups)
+
+	role := rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	for _, group := range groups {
+		sort.Strings(resourcesByGroup[group])
+		role.Rules = append(role.Rules, rbacv1.PolicyRule{
+			APIGroups: []string{group},
+			Resources: resourcesByGroup[group],
+			Verbs:     []string{"list"},
+		})
+	}
+	return role
+}
@@ -0,0 +1,109 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// fakeClients returns a KubeConfig whose clients talk to handler.
+func fakeClients(t *testing.T, handler http.HandlerFunc) *KubeConfig {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	config := &rest.Config{Host: server.URL, QPS: -1}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &KubeConfig{config: config, clientset: clientset}
+}
+
+func TestCheckPermissions(t *testing.T) {
+	tests := []struct {
+		name       string
+		denied     string
+		failing    string
+		wantDenied []string
+		wantErr    bool
+	}{
+		{name: "all allowed"},
+		{name: "denied", denied: "daemonsets", wantDenied: []string{"daemonsets"}},
+		{name: "failed check", failing: "nodes", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			running, maxRunning := 0, 0
+			k := fakeClients(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+				time.Sleep(5 * time.Millisecond)
+				defer func() {
+					mu.Lock()
+					running--
+					mu.Unlock()
+				}()
+
+				var review authorizationv1.SelfSubjectAccessReview
+				if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+					t.Error(err)
+				}
+				resource := review.Spec.ResourceAttributes.Resource
+				if resource == tt.failing {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "BadRequest", "code": 400}`))
+					return
+				}
+				review.Status.Allowed = resource != tt.denied
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				json.NewEncoder(w).Encode(review)
+			})
+
+			permissions, err := k.CheckPermissions(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckPermissions() error = %v, want error %v", err, tt.wantErr)
+			}
+			want := len(Resources)
+			if tt.failing != "" {
+				want--
+			}
+			if len(permissions) != want {
+				t.Fatalf("got %d permissions, want %d", len(permissions), want)
+			}
+			var order []string
+			for _, item := range permissions {
+				order = append(order, item.Resource.Resource)
+			}
+			var wantOrder []string
+			for _, resource := range Resources {
+				if resource.Resource != tt.failing {
+					wantOrder = append(wantOrder, resource.Resource)
+				}
+			}
+			if strings.Join(order, ",") != strings.Join(wantOrder, ",") {
+				t.Errorf("permissions in order %q, want %q", order, wantOrder)
+			}
+			if denied := permissions.Denied(wantOrder...); strings.Join(denied, ",") != strings.Join(tt.wantDenied, ",") {
+				t.Errorf("Denied() = %q, want %q", denied, tt.wantDenied)
+			}
+			if maxRunning > *ParallelismFlag {
+				t.Errorf("%d checks ran at a time, want at most %d", maxRunning, *ParallelismFlag)
+			}
+		})
+	}
+}
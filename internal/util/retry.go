@@ -0,0 +1,71 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// Retry policy for transient API errors: up to maxRetries retries, waiting retryInitialDelay before the first and
+// doubling the wait up to retryMaxDelay after every further attempt.
+const (
+	maxRetries        = 4
+	retryInitialDelay = 500 * time.Millisecond
+	retryMaxDelay     = 8 * time.Second
+)
+
+// isTransient reports whether a failed call is worth retrying: throttling (429), server errors (5xx) and connections
+// that were reset or closed by the server. 501 Not Implemented is permanent.
+func isTransient(err error) bool {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		code := status.Status().Code
+		return code == http.StatusTooManyRequests || (code >= http.StatusInternalServerError && code != http.StatusNotImplemented)
+	}
+	return utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err)
+}
+
+// retry calls call until it succeeds, fails with an error that is not transient, ctx is done or maxRetries retries
+// have been made. Every retry is recorded as a warning for resource in the report.
+func (k *KubeConfig) retry(ctx context.Context, resource string, call func() error) error {
+	delay := retryInitialDelay
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || !isTransient(err) || attempt > maxRetries {
+			return err
+		}
+
+		wait := delay
+		if seconds, ok := apierrors.SuggestsClientDelay(err); ok && time.Duration(seconds)*time.Second > wait {
+			wait = time.Duration(seconds) * time.Second
+		}
+		warn(ctx, resource, fmt.Errorf("%w, retrying in %s (retry %d of %d)", err, wait, attempt, maxRetries))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		if delay *= 2; delay > retryMaxDelay {
+			delay = retryMaxDelay
+		}
+	}
+}
+
+// call makes an API call for resource, giving up on each attempt after --call-timeout and retrying transient errors.
+func (k *KubeConfig) call(ctx context.Context, resource string, call func(ctx context.Context) error) error {
+	return k.retry(ctx, resource, func() error {
+		callCtx := ctx
+		if *CallTimeoutFlag > 0 {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithTimeout(ctx, *CallTimeoutFlag)
+			defer cancel()
+		}
+		return call(callCtx)
+	})
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var pods = schema.GroupResource{Resource: "pods"}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"429 too many requests", apierrors.NewTooManyRequests("slow down", 0), true},
+		{"500 internal error", apierrors.NewInternalError(errors.New("etcd unavailable")), true},
+		{"503 service unavailable", apierrors.NewServiceUnavailable("restarting"), true},
+		{"504 timeout", apierrors.NewTimeoutError("timed out", 0), true},
+		{"501 not implemented", apierrors.NewGenericServerResponse(http.StatusNotImplemented, "list", pods, "", "", 0, false), false},
+		{"400 bad request", apierrors.NewBadRequest("invalid"), false},
+		{"403 forbidden", apierrors.NewForbidden(pods, "", errors.New("denied")), false},
+		{"404 not found", apierrors.NewNotFound(pods, "p1"), false},
+		{"connection reset", &wrappedErr{syscall.ECONNRESET}, true},
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"other", errors.New("certificate signed by unknown authority"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransient(tt.err); got != tt.want {
+				t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// wrappedErr wraps an error the way net.OpError does.
+type wrappedErr struct{ err error }
+
+func (e *wrappedErr) Error() string { return "read tcp: " + e.err.Error() }
+func (e *wrappedErr) Unwrap() error { return e.err }
+
+func TestRetryStopsOnPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{"success", nil, 1},
+		{"forbidden", apierrors.NewForbidden(pods, "", errors.New("denied")), 1},
+		{"not found", apierrors.NewNotFound(pods, "p1"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, warnings := WithWarnings(context.Background(), "Workloads")
+			calls := 0
+			err := (&KubeConfig{}).retry(ctx, "pods", func() error {
+				calls++
+				return tt.err
+			})
+			if err != tt.err || calls != tt.calls || len(warnings.Diagnostics()) != 0 {
+				t.Errorf("retry() = %v after %d calls with %d warnings, want %v after %d calls without warnings",
+					err, calls, len(warnings.Diagnostics()), tt.err, tt.calls)
+			}
+		})
+	}
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		wantErr  bool
+	}{
+		{"recovers", 1, false},
+		{"gives up when cancelled", 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, warnings := WithWarnings(context.Background(), "Workloads")
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			calls := 0
+			err := (&KubeConfig{}).retry(ctx, "pods", func() error {
+				calls++
+				if calls == 2 {
+					// the second failure is not waited for
+					cancel()
+				}
+				if calls <= tt.failures {
+					return apierrors.NewServiceUnavailable("restarting")
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("retry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := len(warnings.Diagnostics()); got != tt.failures {
+				t.Errorf("got %d warnings, want one per retry (%d)", got, tt.failures)
+			}
+		})
+	}
+}
@@ -1,25 +1,52 @@
 package util
 
 import (
+	"sync"
+
 	v1 "k8s.io/api/core/v1"
 	v1net "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 )
 
-// ClusterNodes represents the structure of data to be marshalled into JSON
-type ClusterNodes struct {
-	Cluster string    `json:"cluster"` // Name of the cluster
-	Nodes   []v1.Node `json:"nodes"`   // List of nodes in the cluster
+// NodeInfo is the node section of the report.
+type NodeInfo struct {
+	Cluster string     `json:"cluster"` // API server URL of the cluster
+	Items   []NodeItem `json:"items"`
+}
+
+// NodeItem is a node with all of its labels, annotations, taints, addresses, resources and conditions.
+type NodeItem struct {
+	Name          string             `json:"name"`
+	Labels        map[string]string  `json:"labels,omitempty"`
+	Annotations   map[string]string  `json:"annotations,omitempty"`
+	CreatedAt     metav1.Time        `json:"createdAt"`
+	ProviderID    string             `json:"providerID,omitempty"`
+	PodCIDR       string             `json:"podCIDR,omitempty"`
+	Unschedulable bool               `json:"unschedulable,omitempty"`
+	Taints        []v1.Taint         `json:"taints,omitempty"`
+	Addresses     []v1.NodeAddress   `json:"addresses,omitempty"`
+	Capacity      map[string]string  `json:"capacity,omitempty"`    // resource name to quantity, e.g. "cpu": "4"
+	Allocatable   map[string]string  `json:"allocatable,omitempty"` // resource name to quantity
+	Conditions    []v1.NodeCondition `json:"conditions,omitempty"`
+	System        v1.NodeSystemInfo  `json:"system"` // OS, kernel, container runtime and kubelet versions
 }
 
 type KubeConfig struct {
+	Context      string // kubeconfig context to use, --context or the current context if empty
 	kubeconfig   *string
+	contextName  string
+	clusterName  string
 	config       *rest.Config
 	clientset    *kubernetes.Clientset
+	dynamic      dynamic.Interface
+	metadata     metadata.Interface
+	mu           sync.Mutex // guards workloadlist, which is filled by concurrent collectors
 	workloadlist []WorkloadListItem
 }
 
@@ -30,109 +57,176 @@ type WorkloadListItem struct {
 }
 
 type WorkloadInfoAppType struct {
-	WorkloadType string
-	Workloads    []string
+	WorkloadType string   `json:"workloadType"`
+	Workloads    []string `json:"workloads"`
 }
 
 type WorkloadInfoNamespace struct {
-	Namespace     string
-	WorkloadTypes []WorkloadInfoAppType
+	Namespace     string                `json:"namespace"`
+	WorkloadTypes []WorkloadInfoAppType `json:"workloadTypes"`
 }
 
 type WorkloadInfo struct {
-	Namespaces []WorkloadInfoNamespace
+	Namespaces []WorkloadInfoNamespace `json:"namespaces"`
 }
 
 type StorageClassItem struct {
-	Name        string
-	Provisioner string
-	Parameters  map[string]string
+	Name        string            `json:"name"`
+	Provisioner string            `json:"provisioner"`
+	Parameters  map[string]string `json:"parameters"`
 }
 
 type PersistentVolumeItem struct {
-	Name              string
-	Namespace         string
-	Type              string
-	Size              resource.Quantity
-	AccessModes       []v1.PersistentVolumeAccessMode
-	ReclamationPolicy v1.PersistentVolumeReclaimPolicy
+	Name              string                           `json:"name"`
+	Namespace         string                           `json:"namespace"`
+	Type              string                           `json:"type"`
+	Size              resource.Quantity                `json:"size"`
+	AccessModes       []v1.PersistentVolumeAccessMode  `json:"accessModes"`
+	ReclamationPolicy v1.PersistentVolumeReclaimPolicy `json:"reclamationPolicy"`
 }
 
 type PersistentVolumeClaimItem struct {
-	Namespace    string
-	Name         string
-	Status       v1.PersistentVolumeClaimPhase
-	Volume       string
-	Capacity     resource.Quantity
-	AccessModes  []v1.PersistentVolumeAccessMode
-	StorageClass string
-	Age          metav1.Time
+	Namespace    string                          `json:"namespace"`
+	Name         string                          `json:"name"`
+	Status       v1.PersistentVolumeClaimPhase   `json:"status"`
+	Volume       string                          `json:"volume"`
+	Capacity     resource.Quantity               `json:"capacity"`
+	AccessModes  []v1.PersistentVolumeAccessMode `json:"accessModes"`
+	StorageClass string                          `json:"storageClass"`
+	CreatedAt    metav1.Time                     `json:"createdAt"`
 }
 
 type ConfigMapItem struct {
-	Namespace string
-	Name      string
-	Data      map[string]string
-	Age       metav1.Time
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Data      map[string]string `json:"data"`
+	CreatedAt metav1.Time       `json:"createdAt"`
 }
 
 type ServiceItem struct {
-	Namespace  string
-	Name       string
-	Type       v1.ServiceType
-	ClusterIP  string
-	ExternalIP string // This can be a list or a single IP. Improvement Required to handle multiple IPs.
-	Ports      []v1.ServicePort
-	Age        int
+	Namespace  string           `json:"namespace"`
+	Name       string           `json:"name"`
+	Type       v1.ServiceType   `json:"type"`
+	ClusterIP  string           `json:"clusterIP"`
+	ExternalIP string           `json:"externalIP"` // This can be a list or a single IP. Improvement Required to handle multiple IPs.
+	Ports      []v1.ServicePort `json:"ports"`
+	CreatedAt  metav1.Time      `json:"createdAt"`
 }
 
 // IngressBackendDetail is used to capture the backend service and port
 type IngressBackendDetail struct {
-	ServiceName string
-	ServicePort string
+	ServiceName string `json:"serviceName"`
+	ServicePort string `json:"servicePort"`
 }
 
 // IngressRuleDetail captures the hosts and paths for a rule
 type IngressRuleDetail struct {
-	Host  string
-	Paths []string
+	Host  string   `json:"host"`
+	Paths []string `json:"paths"`
 }
 
 // IngressItem represents an Ingress in the cluster
 type IngressItem struct {
-	Namespace      string
-	Name           string
-	Hosts          []IngressRuleDetail
-	DefaultBackend IngressBackendDetail // This will capture the default backend, if any
-	Addresses      []string
-	Age            int
+	Namespace      string               `json:"namespace"`
+	Name           string               `json:"name"`
+	Hosts          []IngressRuleDetail  `json:"hosts"`
+	DefaultBackend IngressBackendDetail `json:"defaultBackend"` // This will capture the default backend, if any
+	Addresses      []string             `json:"addresses"`
+	CreatedAt      metav1.Time          `json:"createdAt"`
 }
 
 // ClusterRoleItem simplified to just verbs for now, can be expanded to include resources, API groups, etc.
 type ClusterRoleItem struct {
-	Name  string
-	Verbs []string
+	Name  string   `json:"name"`
+	Verbs []string `json:"verbs"`
 }
 
 type ClusterRoleBindingItem struct {
-	Name     string
-	RoleName string           // Name of the ClusterRole that this ClusterRoleBinding refers to
-	Subjects []rbacv1.Subject // List of subjects associated with this ClusterRoleBinding
+	Name     string           `json:"name"`
+	RoleName string           `json:"roleName"` // Name of the ClusterRole that this ClusterRoleBinding refers to
+	Subjects []rbacv1.Subject `json:"subjects"` // List of subjects associated with this ClusterRoleBinding
 }
 
 type ServiceAccountItem struct {
-	Name      string
-	Namespace string
-	Secrets   int    // Count of associated secrets
-	Age       string // Age represented in a human-readable format (hours or days) - might need to change all other types
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Secrets   int         `json:"secrets"` // Count of associated secrets
+	CreatedAt metav1.Time `json:"createdAt"`
 }
 
 type NetworkPolicyItem struct {
-	Name        string
-	Namespace   string
-	PodSelector metav1.LabelSelector
-	Ingress     []v1net.NetworkPolicyIngressRule
-	Egress      []v1net.NetworkPolicyEgressRule
-	PolicyTypes []v1net.PolicyType
-	Age         string
+	Name        string                           `json:"name"`
+	Namespace   string                           `json:"namespace"`
+	PodSelector metav1.LabelSelector             `json:"podSelector"`
+	Ingress     []v1net.NetworkPolicyIngressRule `json:"ingress"`
+	Egress      []v1net.NetworkPolicyEgressRule  `json:"egress"`
+	PolicyTypes []v1net.PolicyType               `json:"policyTypes"`
+	CreatedAt   metav1.Time                      `json:"createdAt"`
+}
+
+// ContainerImageItem is one container or initContainer of a pod together with the workload that owns the pod.
+type ContainerImageItem struct {
+	Image         string `json:"image"`
+	Digest        string `json:"digest"` // Repository digest reported in the pod status, e.g. sha256:...
+	Namespace     string `json:"namespace"`
+	Pod           string `json:"pod"`
+	Container     string `json:"container"`
+	InitContainer bool   `json:"initContainer"`
+	OwnerKind     string `json:"ownerKind"` // Kind of the top level owner, e.g. Deployment, or Pod for bare pods
+	OwnerName     string `json:"ownerName"`
+}
+
+// HelmChartItem is a chart installed by the Helm controller embedded in RKE2 and K3s, such as rke2-canal.
+type HelmChartItem struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	Chart           string `json:"chart"`
+	Version         string `json:"version"`
+	Repo            string `json:"repo,omitempty"`
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+}
+
+// ClusterVersionItem is the ClusterVersion of an OpenShift cluster, which the cluster version operator updates the
+// cluster by.
+type ClusterVersionItem struct {
+	Name      string                 `json:"name"`
+	Version   string                 `json:"version"`             // the version being or last applied
+	Channel   string                 `json:"channel,omitempty"`   // update channel, e.g. stable-4.13
+	ClusterID string                 `json:"clusterID,omitempty"` // unique ID of the cluster
+	History   []ClusterVersionUpdate `json:"history,omitempty"`   // updates, most recent first
+}
+
+// ClusterVersionUpdate is an update in the history of a ClusterVersion.
+type ClusterVersionUpdate struct {
+	Version     string `json:"version"`
+	State       string `json:"state"`                 // Completed or Partial
+	StartedTime string `json:"startedTime,omitempty"` // RFC 3339
+}
+
+// CustomResourceDefinitionItem is an installed CRD together with how many instances of its kind exist.
+type CustomResourceDefinitionItem struct {
+	Name           string                `json:"name"` // e.g. certificates.cert-manager.io
+	Group          string                `json:"group"`
+	Kind           string                `json:"kind"`
+	Plural         string                `json:"plural"`
+	Scope          string                `json:"scope"`               // Namespaced or Cluster
+	Versions       []string              `json:"versions"`            // served versions
+	StoredVersions []string              `json:"storedVersions"`      // versions objects were ever persisted in, from the CRD status
+	Total          *int                  `json:"total,omitempty"`     // omitted if the instances could not be counted
+	Instances      []CustomResourceCount `json:"instances,omitempty"` // per namespace
+}
+
+// CustomResourceCount is the number of instances of a custom resource kind in a namespace, "" for cluster scoped kinds.
+type CustomResourceCount struct {
+	Namespace string `json:"namespace"`
+	Count     int    `json:"count"`
+}
+
+// CustomResourceDump holds every object of a kind selected with --custom-resource.
+type CustomResourceDump struct {
+	Group    string                   `json:"group"`
+	Version  string                   `json:"version"`
+	Kind     string                   `json:"kind"`
+	Resource string                   `json:"resource"`
+	Items    []map[string]interface{} `json:"items"` // the objects as returned by the API server, without managedFields
 }